@@ -0,0 +1,17 @@
+data "cdn77_conditional_features_evaluate" "example" {
+  configuration = cdn77_cdn.example.conditional_features.configuration
+
+  request = {
+    path      = "/atp/video.mp4"
+    headers   = { "X-Debug" = "1" }
+    country   = "CZ"
+    client_ip = "192.0.2.1"
+  }
+}
+
+check "cors_headers" {
+  assert {
+    condition     = contains([for action in data.cdn77_conditional_features_evaluate.example.actions : action.name], "response_hdr")
+    error_message = "Requests to /atp must get the CORS headers"
+  }
+}
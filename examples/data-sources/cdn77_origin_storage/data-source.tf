@@ -0,0 +1,3 @@
+data "cdn77_origin_storage" "example" {
+  id = "a8ef9a76-7d3c-4fe4-9df8-3cb3d6b9ef4c"
+}
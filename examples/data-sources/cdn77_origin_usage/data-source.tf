@@ -0,0 +1,3 @@
+data "cdn77_origin_usage" "example" {
+  origin_id = "8e3a5e3e-2f7b-4d6c-9a1e-3f1b2c4d5e6f"
+}
@@ -0,0 +1,3 @@
+data "cdn77_ssl_usage" "example" {
+  ssl_id = "ae8e3e3a-7b2f-4c6d-8e1a-1b3f4d2c6e5f"
+}
@@ -0,0 +1,5 @@
+ephemeral "cdn77_signed_url" "example" {
+  cdn_id    = 1234567890
+  path      = "/videos/movie.mp4"
+  valid_for = "12h"
+}
@@ -0,0 +1,25 @@
+locals {
+  certificate = trimspace(file("${path.module}/cert.pem"))
+  private_key = trimspace(file("${path.module}/key.pem"))
+  cert_info   = provider::cdn77::certificate_info(local.certificate, local.private_key)
+}
+
+resource "cdn77_ssl" "example" {
+  certificate = local.certificate
+  private_key = local.private_key
+
+  lifecycle {
+    precondition {
+      condition     = local.cert_info.key_matches
+      error_message = "The private key doesn't belong to the certificate."
+    }
+    precondition {
+      condition     = contains(local.cert_info.subjects, "cdn.example.com")
+      error_message = "The certificate doesn't cover cdn.example.com."
+    }
+    precondition {
+      condition     = timecmp(local.cert_info.not_after, timeadd(plantimestamp(), "720h")) > 0
+      error_message = "The certificate expires in less than 30 days."
+    }
+  }
+}
@@ -0,0 +1,4 @@
+resource "cdn77_origin_url" "example" {
+  label = "My origin"
+  url   = provider::cdn77::origin_url("https", "my-totally-random-custom-host.com", 8080, "images")
+}
@@ -0,0 +1,13 @@
+locals {
+  origin = provider::cdn77::parse_origin_url("https://my-totally-random-custom-host.com:8080/images")
+}
+
+resource "cdn77_origin_url" "example" {
+  label = "My origin"
+  url_parts = {
+    scheme    = local.origin.scheme
+    host      = local.origin.host
+    port      = local.origin.port
+    base_path = local.origin.base_path
+  }
+}
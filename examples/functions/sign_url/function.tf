@@ -0,0 +1,10 @@
+output "signed_url" {
+  value = provider::cdn77::sign_url(
+    "https://${cdn77_cdn.example.url}/videos/movie.mp4",
+    cdn77_cdn.example.secure_token.type,
+    cdn77_cdn.example.secure_token.token,
+    1893456000,
+    null,
+  )
+  sensitive = true
+}
@@ -3,3 +3,34 @@ resource "cdn77_cdn" "example" {
   origin_id = cdn77_origin_url.example.id
   cnames    = ["cdn.example.com"]
 }
+
+resource "cdn77_cdn" "with_rules" {
+  label     = "Videos for example.com"
+  origin_id = cdn77_origin_url.example.id
+
+  # Fail the plan when the rules contain likely mistakes (e.g. duplicate conditions)
+  conditional_features_lint = "error"
+
+  conditional_features = {
+    rule = [
+      {
+        if = {
+          operator = "OR"
+          conditions = [
+            { type = "path-prefix", args = { prefix = "/live/" } },
+            { type = "var", args = { key = "set-cors", value = "true" } },
+          ]
+        }
+        then = [
+          {
+            name = "response_hdr"
+            config = jsonencode({
+              response_hdr_name  = "Access-Control-Allow-Origin"
+              response_hdr_value = "*"
+            })
+          },
+        ]
+      },
+    ]
+  }
+}
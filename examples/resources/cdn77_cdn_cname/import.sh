@@ -0,0 +1,7 @@
+$ terraform import cdn77_cdn_cname.example <cdn_id>/<cname>
+
+# <cdn_id> must be the ID (unsigned integer) of the CDN
+# <cname> must be the CNAME assigned to the CDN
+
+# Example:
+$ terraform import cdn77_cdn_cname.example 1837865409/shop-cdn.example.com
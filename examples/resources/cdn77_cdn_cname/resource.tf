@@ -0,0 +1,13 @@
+resource "cdn77_cdn" "shared" {
+  label     = "Shared CDN for example.com"
+  origin_id = cdn77_origin_url.example.id
+  cnames    = ["cdn.example.com"]
+
+  # Keep the CNAMEs managed by the cdn77_cdn_cname resources below
+  ignore_external_cnames = true
+}
+
+resource "cdn77_cdn_cname" "shop" {
+  cdn_id = cdn77_cdn.shared.id
+  cname  = "shop-cdn.example.com"
+}
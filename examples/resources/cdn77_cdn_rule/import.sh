@@ -0,0 +1,8 @@
+$ terraform import cdn77_cdn_rule.example <cdn_id>/<priority>
+
+# <cdn_id> must be the ID (unsigned integer) of the CDN
+# <priority> must be the priority of the rule
+# Secrets of the rule aren't imported; they are set on the next apply.
+
+# Example:
+$ terraform import cdn77_cdn_rule.example 1837865409/10
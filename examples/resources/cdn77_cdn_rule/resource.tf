@@ -0,0 +1,35 @@
+resource "cdn77_cdn" "shared" {
+  label     = "Shared CDN for example.com"
+  origin_id = cdn77_origin_url.example.id
+
+  # Keep the rules managed by the cdn77_cdn_rule resources below
+  conditional_features_ignore_external_rules = true
+}
+
+resource "cdn77_cdn_rule" "api_cors" {
+  cdn_id   = cdn77_cdn.shared.id
+  priority = 10
+
+  if = {
+    conditions = [{ type = "path-prefix", args = { prefix = "/api/" } }]
+  }
+  then = [
+    {
+      name = "response_hdr"
+      config = jsonencode({
+        response_hdr_name  = "Access-Control-Allow-Origin"
+        response_hdr_value = "*"
+      })
+    },
+  ]
+}
+
+resource "cdn77_cdn_rule" "no_cache_for_previews" {
+  cdn_id   = cdn77_cdn.shared.id
+  priority = 20
+
+  if = {
+    conditions = [{ type = "query-param", args = { key = "preview" } }]
+  }
+  then = [{ name = "cache_disable" }]
+}
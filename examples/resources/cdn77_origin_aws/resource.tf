@@ -5,3 +5,13 @@ resource "cdn77_origin_aws" "example" {
   access_key_id     = "23478207027842073230762374023"
   access_key_secret = "VWK92izmd7zpY8Khs/Dllv4yLYc4sFWNyg2XtuNF"
 }
+
+# With Terraform 1.11+ the secret can be passed via a write-only attribute so it's never stored in the state
+resource "cdn77_origin_aws" "write_only" {
+  label                        = "Assets AWS bucket for example.com"
+  url                          = "https://examplecom-static-assets.s3.eu-central-1.amazonaws.com"
+  region                       = "eu-central-1"
+  access_key_id                = "23478207027842073230762374023"
+  access_key_secret_wo         = "VWK92izmd7zpY8Khs/Dllv4yLYc4sFWNyg2XtuNF"
+  access_key_secret_wo_version = 1
+}
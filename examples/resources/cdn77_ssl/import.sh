@@ -2,6 +2,7 @@ $ terraform import cdn77_ssl.example <id>,<private_key>
 
 # <id> must be the ID (UUID) of the SSL certificate
 # <privateKey> must be an entire private key (including PEM headers) encoded via base64.
+# When the private key is managed by the write-only "private_key_wo" attribute, import only by <id>.
 
 # Example:
 $ key=$(base64 --wrap=0 <<EOL
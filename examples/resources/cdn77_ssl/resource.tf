@@ -2,3 +2,10 @@ resource "cdn77_ssl" "example" {
   certificate = file("${path.module}/my-cert.pem")
   private_key = file("${path.module}/my-key.pem")
 }
+
+# With Terraform 1.11+ the private key can be passed via a write-only attribute so it's never stored in the state
+resource "cdn77_ssl" "write_only" {
+  certificate            = file("${path.module}/my-cert.pem")
+  private_key_wo         = file("${path.module}/my-key.pem")
+  private_key_wo_version = 1
+}
@@ -0,0 +1,3 @@
+$ terraform import cdn77_ssl_rotation.example <ssl_id>
+
+# <ssl_id> must be the ID (UUID) of the SSL certificate the CDNs currently use
@@ -0,0 +1,14 @@
+# A change of the certificate uploads a new one, switches all CDNs using the old one to it and deletes the old one
+resource "cdn77_ssl" "example" {
+  certificate       = file("${path.module}/my-cert.pem")
+  private_key       = file("${path.module}/my-key.pem")
+  replace_on_change = true
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "cdn77_ssl_rotation" "example" {
+  ssl_id = cdn77_ssl.example.id
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func GetProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
@@ -132,6 +133,17 @@ func Run(t *testing.T, checkDestroy resource.TestCheckFunc, steps ...resource.Te
 	})
 }
 
+// RunWriteOnly runs a test which uses write-only attributes; such tests are skipped below Terraform 1.11.
+func RunWriteOnly(t *testing.T, checkDestroy resource.TestCheckFunc, steps ...resource.TestStep) {
+	t.Helper()
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_11_0)},
+		ProtoV6ProviderFactories: GetProviderFactories(),
+		CheckDestroy:             checkDestroy,
+		Steps:                    steps,
+	})
+}
+
 func CheckAndAssignAttr(rsc string, attr string, target *string) resource.TestCheckFunc {
 	return resource.TestCheckResourceAttrWith(rsc, attr, func(value string) error {
 		*target = value
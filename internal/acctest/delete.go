@@ -18,6 +18,8 @@ func DeleteOrigin(client cdn77.ClientWithResponsesInterface, originType string,
 		response, err = client.OriginDeleteAwsWithResponse(context.Background(), id)
 	case origin.TypeObjectStorage:
 		response, err = client.OriginDeleteObjectStorageWithResponse(context.Background(), id)
+	case origin.TypeStorage:
+		response, err = client.OriginDeleteStorageWithResponse(context.Background(), id)
 	case origin.TypeUrl:
 		response, err = client.OriginDeleteUrlWithResponse(context.Background(), id)
 	default:
@@ -18,12 +18,16 @@ type Resource string
 
 const (
 	Cdn                 = Resource("cdn")
+	CdnCname            = Resource("cdn_cname")
+	CdnRule             = Resource("cdn_rule")
 	Cdns                = Resource("cdns")
 	ObjectStorages      = Resource("object_storages")
 	OriginAws           = Resource("origin_aws")
 	OriginObjectStorage = Resource("origin_object_storage")
+	OriginStorage       = Resource("origin_storage")
 	OriginUrl           = Resource("origin_url")
 	Ssl                 = Resource("ssl")
+	SslRotation         = Resource("ssl_rotation")
 	Ssls                = Resource("ssls")
 )
 
@@ -35,6 +39,10 @@ func ResourceFactory(rsc Resource) func() resource.Resource {
 		switch rsc {
 		case Cdn:
 			return &cdn.Resource{BaseResource: baseResource}
+		case CdnCname:
+			return &cdn.CnameResource{BaseResource: baseResource}
+		case CdnRule:
+			return &cdn.RuleResource{BaseResource: baseResource}
 		case OriginAws:
 			return &origin.AwsResource{BaseResource: baseResource}
 		case OriginObjectStorage:
@@ -43,6 +51,8 @@ func ResourceFactory(rsc Resource) func() resource.Resource {
 			return &origin.UrlResource{BaseResource: baseResource}
 		case Ssl:
 			return &ssl.Resource{BaseResource: baseResource}
+		case SslRotation:
+			return &ssl.RotationResource{BaseResource: baseResource}
 		default:
 			panic(fmt.Sprintf("unexpected resource type %q", rsc))
 		}
@@ -65,6 +75,8 @@ func DataSourceFactory(rsc Resource) func() datasource.DataSource {
 			return &origin.AwsDataSource{BaseDataSource: baseDataSource}
 		case OriginObjectStorage:
 			return &origin.ObjectStorageDataSource{BaseDataSource: baseDataSource}
+		case OriginStorage:
+			return &origin.StorageDataSource{BaseDataSource: baseDataSource}
 		case OriginUrl:
 			return &origin.UrlDataSource{BaseDataSource: baseDataSource}
 		case Ssl:
@@ -81,14 +93,22 @@ func resourceSchemaProviderAndReader(rsc Resource) (func() rsc_schema.Schema, ut
 	switch rsc {
 	case Cdn, Cdns:
 		return cdn.CreateResourceSchema, util.NewUniversalReader(&cdn.Reader{})
+	case CdnCname:
+		return cdn.CreateCnameResourceSchema, nil
+	case CdnRule:
+		return cdn.CreateRuleResourceSchema, nil
 	case OriginAws:
 		return origin.CreateAwsResourceSchema, util.NewUniversalReader(&origin.AwsReader{})
 	case OriginObjectStorage:
 		return origin.CreateObjectStorageResourceSchema, util.NewUniversalReader(&origin.ObjectStorageReader{})
+	case OriginStorage:
+		return origin.CreateStorageResourceSchema, util.NewUniversalReader(&origin.StorageReader{})
 	case OriginUrl:
 		return origin.CreateUrlResourceSchema, util.NewUniversalReader(&origin.UrlReader{})
 	case Ssl:
 		return ssl.CreateResourceSchema, util.NewUniversalReader(&ssl.Reader{})
+	case SslRotation:
+		return ssl.CreateRotationResourceSchema, nil
 	case Ssls:
 		return ssl.CreateBaseResourceSchema, nil
 	default:
@@ -101,20 +121,64 @@ func dataSourceSchemaProviderAndReader(rsc Resource) (func() ds_schema.Schema, u
 		return object_storages.CreateSchema, nil
 	}
 
-	schemaProvider, reader := resourceSchemaProviderAndReader(rsc)
+	schemaProvider, _ := resourceSchemaProviderAndReader(rsc)
+	excludedAttrs := resourceOnlyAttrs(rsc)
 
 	switch rsc {
-	case Cdn, OriginAws, OriginObjectStorage, OriginUrl, Ssl:
-		return toDataSourceSchema(schemaProvider, "id"), reader
+	case Cdn, OriginAws, OriginObjectStorage, OriginStorage, OriginUrl, Ssl:
+		return toDataSourceSchema(schemaProvider, excludedAttrs, "id"), dataSourceReader(rsc)
 	case Cdns, Ssls:
-		return toDataSourceSchema(schemaProvider), reader
+		return toDataSourceSchema(schemaProvider, excludedAttrs), dataSourceReader(rsc)
 	default:
 		panic(fmt.Sprintf("unexpected resource type %q", rsc))
 	}
 }
 
-func toDataSourceSchema(schemaProvider func() rsc_schema.Schema, requiredAttrs ...string) func() ds_schema.Schema {
+// dataSourceReader returns the reader of the data source model, which leaves out the resource-only attributes.
+func dataSourceReader(rsc Resource) util.Reader {
+	switch rsc {
+	case Cdn, Cdns:
+		return cdn.NewDataSourceReader()
+	case OriginAws:
+		return origin.NewAwsDataSourceReader()
+	case OriginObjectStorage:
+		return origin.NewObjectStorageDataSourceReader()
+	case OriginStorage:
+		return util.NewUniversalReader(&origin.StorageReader{})
+	case OriginUrl:
+		return origin.NewUrlDataSourceReader()
+	case Ssl:
+		return ssl.NewDataSourceReader()
+	default:
+		return nil
+	}
+}
+
+func resourceOnlyAttrs(rsc Resource) []string {
+	switch rsc {
+	case Cdn, Cdns:
+		return cdn.ResourceOnlyAttrs
+	case OriginAws:
+		return origin.AwsResourceOnlyAttrs
+	case OriginObjectStorage:
+		return origin.ObjectStorageResourceOnlyAttrs
+	case OriginUrl:
+		return origin.UrlResourceOnlyAttrs
+	case Ssl:
+		return ssl.ResourceOnlyAttrs
+	default:
+		return nil
+	}
+}
+
+func toDataSourceSchema(
+	schemaProvider func() rsc_schema.Schema,
+	excludedAttrs []string,
+	requiredAttrs ...string,
+) func() ds_schema.Schema {
 	return func() ds_schema.Schema {
-		return util.NewResourceDataSourceSchemaConverter(requiredAttrs...).Convert(schemaProvider())
+		converter := util.NewResourceDataSourceSchemaConverter(requiredAttrs...).WithoutAttrs(excludedAttrs...)
+
+		return converter.Convert(schemaProvider())
 	}
 }
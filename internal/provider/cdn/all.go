@@ -14,7 +14,7 @@ import (
 )
 
 type AllModel struct {
-	Cdns []Model `tfsdk:"cdns"`
+	Cdns []DataSourceModel `tfsdk:"cdns"`
 }
 
 var _ datasource.DataSourceWithConfigure = &AllDataSource{}
@@ -53,20 +53,20 @@ func (d *AllDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp
 	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(summaries *[]cdn77.CdnSummary) {
 		wg := sync.WaitGroup{}
 		mu := sync.Mutex{}
-		cdns := make([]Model, 0, len(*summaries))
+		cdns := make([]DataSourceModel, 0, len(*summaries))
 
 		wg.Add(len(*summaries))
 
 		for _, summary := range *summaries {
 			go func() {
-				var data any = Model{Id: types.Int64Value(int64(summary.Id))}
+				var data any = DataSourceModel{Id: types.Int64Value(int64(summary.Id))}
 
 				ds := d.BaseDataSource.Reader().Fill(ctx, d.Client, &data)
 
 				mu.Lock()
 
 				if ds == nil {
-					cdns = append(cdns, data.(Model))
+					cdns = append(cdns, data.(DataSourceModel))
 				} else {
 					diags.Append(ds...)
 				}
@@ -77,7 +77,7 @@ func (d *AllDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp
 		}
 
 		if wg.Wait(); !diags.HasError() {
-			slices.SortStableFunc(cdns, func(a, b Model) int {
+			slices.SortStableFunc(cdns, func(a, b DataSourceModel) int {
 				return cmp.Compare(a.Id.ValueInt64(), b.Id.ValueInt64())
 			})
 			diags.Append(resp.State.Set(ctx, AllModel{Cdns: cdns})...)
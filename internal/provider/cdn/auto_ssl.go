@@ -0,0 +1,133 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// autoSslId is the value of "ssl.ssl_id" which selects the SSL certificate automatically.
+const autoSslId = "auto"
+
+// resolvePlannedSslId sets "ssl.resolved_ssl_id" in the plan to the configured SSL certificate or, with "ssl_id" set
+// to "auto", to the selected one. The selection is unknown until the CNAMEs are known.
+func (r *Resource) resolvePlannedSslId(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	diags := &resp.Diagnostics
+	typePath := path.Root("ssl").AtName("type")
+	sslIdPath := path.Root("ssl").AtName("ssl_id")
+	resolvedPath := path.Root("ssl").AtName("resolved_ssl_id")
+	var sslType, sslId types.String
+	var cnames types.Set
+
+	diags.Append(req.Plan.GetAttribute(ctx, typePath, &sslType)...)
+	diags.Append(req.Plan.GetAttribute(ctx, sslIdPath, &sslId)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("cnames"), &cnames)...)
+
+	if diags.HasError() || sslId.IsUnknown() {
+		return
+	}
+
+	if sslId.ValueString() != autoSslId {
+		diags.Append(resp.Plan.SetAttribute(ctx, resolvedPath, sslId)...)
+
+		return
+	}
+
+	if !sslType.IsUnknown() && sslType.ValueString() != string(cdn77.SNI) {
+		diags.AddAttributeError(
+			sslIdPath,
+			"Invalid SSL certificate ID",
+			fmt.Sprintf(`SSL certificate ID %q requires type %q`, autoSslId, cdn77.SNI),
+		)
+
+		return
+	}
+
+	if cnames.IsUnknown() || r.BaseResource == nil || r.Client == nil {
+		diags.Append(resp.Plan.SetAttribute(ctx, resolvedPath, types.StringUnknown())...)
+
+		return
+	}
+
+	cnameList, ok := util.StringSetToSlice(ctx, diags, path.Root("cnames"), cnames)
+	if !ok {
+		return
+	}
+
+	if selected := r.selectSsl(ctx, diags, sslIdPath, cnameList); selected != "" {
+		diags.Append(resp.Plan.SetAttribute(ctx, resolvedPath, selected)...)
+	}
+}
+
+// selectSsl returns the ID of the non-expired SNI certificate covering all the CNAMEs with the latest expiration.
+func (r *Resource) selectSsl(ctx context.Context, diags *diag.Diagnostics, p path.Path, cnames []string) string {
+	const errMessage = "Failed to select SSL certificate"
+
+	if len(cnames) == 0 {
+		diags.AddAttributeError(p, errMessage, fmt.Sprintf(`SSL certificate ID %q requires some "cnames"`, autoSslId))
+
+		return ""
+	}
+
+	response, err := r.Client.SslSniListWithResponse(ctx)
+	if err != nil {
+		diags.AddAttributeError(p, errMessage, err.Error())
+
+		return ""
+	}
+
+	var selected *cdn77.Ssl
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *cdn77.SslList) {
+		now := time.Now()
+
+		for _, ssl := range *list {
+			if !ssl.ExpiresAt.After(now) || !coversHostnames(ssl.Cnames, cnames) {
+				continue
+			}
+
+			if selected == nil || ssl.ExpiresAt.After(selected.ExpiresAt) ||
+				ssl.ExpiresAt.Equal(selected.ExpiresAt) && ssl.Id < selected.Id {
+				selected = &ssl
+			}
+		}
+	})
+
+	if diags.HasError() {
+		return ""
+	}
+
+	if selected == nil {
+		diags.AddAttributeError(p, errMessage, fmt.Sprintf(
+			"No SNI certificate that hasn't expired covers all CNAMEs: %s",
+			strings.Join(cnames, ", "),
+		))
+
+		return ""
+	}
+
+	return selected.Id
+}
+
+func coversHostnames(subjects []string, hostnames []string) bool {
+	for _, hostname := range hostnames {
+		if !slices.ContainsFunc(subjects, func(subject string) bool { return coversHostname(subject, hostname) }) {
+			return false
+		}
+	}
+
+	return true
+}
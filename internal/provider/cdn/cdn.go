@@ -23,6 +23,14 @@ var (
 	_ resource.ResourceWithConfigure        = &Resource{}
 	_ resource.ResourceWithConfigValidators = &Resource{}
 	_ resource.ResourceWithImportState      = &Resource{}
+	_ resource.ResourceWithModifyPlan       = &Resource{}
+	_ resource.ResourceWithUpgradeState     = &Resource{}
+)
+
+// Values of the "on_create_failure" attribute.
+const (
+	onCreateFailureDelete = "delete"
+	onCreateFailureKeep   = "keep"
 )
 
 type Resource struct {
@@ -30,7 +38,76 @@ type Resource struct {
 }
 
 func (*Resource) ConfigValidators(context.Context) []resource.ConfigValidator {
-	return []resource.ConfigValidator{NewNullableListsConfigValidator()}
+	return []resource.ConfigValidator{NewNullableListsConfigValidator(), IgnoredSectionsConfigValidator{}}
+}
+
+func (r *Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if planIgnoredSections(ctx, req, resp); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if lintPlannedConditionalFeatures(ctx, req, resp); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.resolvePlannedSslId(ctx, req, resp); resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.validateReferences(ctx, req, resp)
+}
+
+func lintPlannedConditionalFeatures(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	diags := &resp.Diagnostics
+	configurationPath := path.Root("conditional_features").AtName("configuration")
+	severityPath := path.Root("conditional_features_lint")
+	var configuration util.JsonValue
+	var severity types.String
+
+	if diags.Append(req.Plan.GetAttribute(ctx, configurationPath, &configuration)...); diags.HasError() {
+		return
+	}
+
+	if diags.Append(req.Plan.GetAttribute(ctx, severityPath, &severity)...); diags.HasError() {
+		return
+	}
+
+	if configuration.IsNull() || configuration.IsUnknown() || severity.IsUnknown() {
+		return
+	}
+
+	severityValue := lintSeverityWarning
+	if !severity.IsNull() {
+		severityValue = severity.ValueString()
+	}
+
+	if severityValue == lintSeverityOff {
+		return
+	}
+
+	// Invalid configuration is reported by its validator.
+	if !validateConditionalFeaturesConfiguration(&diag.Diagnostics{}, configurationPath, configuration.ValueString()) {
+		return
+	}
+
+	for _, issue := range lintConditionalFeatures(configuration.ValueString()) {
+		summary := fmt.Sprintf("Conditional features rule #%d %s", issue.rule, issue.message)
+		detail := `Set "conditional_features_lint" to "off" to disable the check of conditional feature rules.`
+
+		if severityValue == lintSeverityError {
+			diags.AddAttributeError(configurationPath, summary, detail)
+		} else {
+			diags.AddAttributeWarning(configurationPath, summary, detail)
+		}
+	}
 }
 
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -41,6 +118,10 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	if !readWriteOnlyValues(ctx, diags, req.Config, &data) {
+		return
+	}
+
 	var cnamesPtr *[]string
 
 	if !data.Cnames.IsNull() {
@@ -54,36 +135,87 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 
 	const errMessage = "Failed to create CDN"
 
+	marker, err := util.NewIdempotencyMarker()
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	// The marker is removed from the note by the edit after creation; the reader leaves it out in case the edit fails.
 	request := cdn77.CdnAddJSONRequestBody{
 		OriginId: data.OriginId.ValueString(),
 		Label:    data.Label.ValueString(),
 		Cnames:   cnamesPtr,
-		Note:     util.StringValueToNullable(data.Note),
+		Note:     marker.Mark(util.StringValueToNullable(data.Note)),
 	}
 
 	response, err := r.Client.CdnAddWithResponse(ctx, request)
-	if err != nil {
-		diags.AddError(errMessage, err.Error())
 
-		return
+	var summary *cdn77.CdnSummary
+
+	if util.IsLostResponse(response, err) {
+		var cancel context.CancelFunc
+		ctx, cancel = util.LostResponseContext(ctx)
+		defer cancel()
+
+		if summary = r.findCreatedCdn(ctx, diags, marker); diags.HasError() {
+			return
+		}
 	}
 
-	var id int
+	if summary == nil {
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
 
-	util.ProcessResponse(diags, response, errMessage, response.JSON201, func(detail *cdn77.CdnSummary) {
-		id = detail.Id
-		data.Id = types.Int64Value(int64(id))
-		data.CreationTime = types.StringValue(detail.CreationTime.Format(time.DateTime))
-		data.Url = types.StringValue(detail.Url)
-	})
+			return
+		}
 
-	if diags.HasError() {
-		return
+		util.ProcessResponse(diags, response, errMessage, response.JSON201, func(detail *cdn77.CdnSummary) {
+			summary = detail
+		})
+
+		if diags.HasError() {
+			return
+		}
 	}
 
+	id := summary.Id
+	data.Id = types.Int64Value(int64(id))
+	data.CreationTime = types.StringValue(summary.CreationTime.Format(time.DateTime))
+	data.Url = types.StringValue(summary.Url)
+
 	r.editCdnAfterCreation(ctx, diags, id, data, &resp.State)
 }
 
+// findCreatedCdn looks for the CDN created by a request whose response was lost; the result is nil if there is no such
+// CDN.
+func (r *Resource) findCreatedCdn(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	marker util.IdempotencyMarker,
+) *cdn77.CdnSummary {
+	const errMessage = "Failed to look for CDN created by a lost request"
+
+	response, err := r.Client.CdnListWithResponse(ctx)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil
+	}
+
+	var found *cdn77.CdnSummary
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *[]cdn77.CdnSummary) {
+		idAndNote := func(c cdn77.CdnSummary) (string, nullable.Nullable[string]) {
+			return strconv.Itoa(c.Id), c.Note
+		}
+		found = util.FindMarked(diags, "CDN", marker, *list, idAndNote)
+	})
+
+	return found
+}
+
 func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	diags := &resp.Diagnostics
 	var data Model
@@ -92,11 +224,61 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	if !readWriteOnlyValues(ctx, diags, req.Config, &data) {
+		return
+	}
+
+	var state Model
+	if diags.Append(req.State.Get(ctx, &state)...); diags.HasError() {
+		return
+	}
+
 	request, ok := r.createEditRequest(ctx, diags, data)
 	if !ok {
 		return
 	}
 
+	// Sections that didn't change aren't sent, so settings changed outside of Terraform (or not modeled by
+	// the provider) in those sections are kept. The whole request is sent if it can't be built from the state.
+	if stateRequest, ok := r.createEditRequest(ctx, &diag.Diagnostics{}, state); ok {
+		comparedRequest, ok := r.createEditRequest(ctx, diags, withoutUnchangedWriteOnlyValues(data, state))
+		if !ok {
+			return
+		}
+
+		changedRequest, ok := withoutUnchangedSections(diags, request, comparedRequest, stateRequest)
+		if !ok {
+			return
+		}
+
+		// Only attributes not sent to the API (e.g. "conditional_features_lint") changed.
+		if changedRequest == nil {
+			diags.Append(resp.State.Set(ctx, data)...)
+
+			return
+		}
+
+		request = *changedRequest
+	}
+
+	id := data.Id.ValueInt64()
+
+	if data.IgnoreExternalCnames.ValueBool() && request.Cnames != nil {
+		defer lockCdn(id, cdnSectionCnames)()
+
+		if !keepExternalCnames(ctx, r.Client, diags, id, ownCnames(state), &request) {
+			return
+		}
+	}
+
+	if data.IgnoreExternalRules.ValueBool() && request.ConditionalFeatures != nil {
+		defer lockCdn(id, cdnSectionConditionalFeatures)()
+
+		if !keepExternalRules(ctx, r.Client, diags, id, ownSecrets(state), request.ConditionalFeatures) {
+			return
+		}
+	}
+
 	const errMessage = "Failed to update CDN"
 
 	response, err := r.Client.CdnEditWithResponse(ctx, int(data.Id.ValueInt64()), request)
@@ -121,6 +303,10 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 
 	const errMessage = "Failed to delete CDN"
 
+	if util.IsDeletionProtected(diags, data.DeletionProtection, errMessage) {
+		return
+	}
+
 	response, err := r.Client.CdnDeleteWithResponse(ctx, int(data.Id.ValueInt64()))
 	if err != nil {
 		diags.AddError(errMessage, err.Error())
@@ -149,6 +335,44 @@ func (*Resource) ImportState(
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
+func (*Resource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{0: {StateUpgrader: upgradeStateV0}}
+}
+
+// upgradeStateV0 computes "conditional_features.rule" from "conditional_features.configuration" and rewrites the
+// configuration to the canonical form, so it matches the value read from the API. Nothing else changed in the schema,
+// so the raw state is read using the current schema where the new attribute is null.
+func upgradeStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	diags := &resp.Diagnostics
+
+	rawState, err := req.RawState.Unmarshal(resp.State.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		diags.AddError("Failed to upgrade CDN state", err.Error())
+
+		return
+	}
+
+	var data Model
+	state := tfsdk.State{Schema: resp.State.Schema, Raw: rawState}
+
+	if diags.Append(state.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	if cf := data.ConditionalFeatures; cf != nil {
+		cf.Rule = ruleListFromConfiguration(ctx, diags, cf.Configuration)
+
+		if !cf.Configuration.IsNull() {
+			// Invalid configurations are kept as they are; they are replaced by the value read from the API anyway.
+			if canonical, err := canonicalConfiguration([]byte(cf.Configuration.ValueString())); err == nil {
+				cf.Configuration = util.NewJsonValue(canonical)
+			}
+		}
+	}
+
+	diags.Append(resp.State.Set(ctx, data)...)
+}
+
 func (r *Resource) editCdnAfterCreation(
 	ctx context.Context,
 	diags *diag.Diagnostics,
@@ -158,7 +382,7 @@ func (r *Resource) editCdnAfterCreation(
 ) {
 	editRequest, ok := r.createEditRequest(ctx, diags, data)
 	if !ok {
-		r.deleteAfterFailedEdit(ctx, diags, id)
+		r.handleFailedEdit(ctx, diags, id, data, state)
 
 		return
 	}
@@ -168,7 +392,7 @@ func (r *Resource) editCdnAfterCreation(
 	editResponse, err := r.Client.CdnEditWithResponse(ctx, id, editRequest)
 	if err != nil {
 		diags.AddError(editErrMessage, err.Error())
-		r.deleteAfterFailedEdit(ctx, diags, id)
+		r.handleFailedEdit(ctx, diags, id, data, state)
 
 		return
 	}
@@ -178,7 +402,57 @@ func (r *Resource) editCdnAfterCreation(
 	})
 
 	if diags.HasError() {
+		r.handleFailedEdit(ctx, diags, id, data, state)
+	}
+}
+
+// handleFailedEdit either deletes the CDN or (with "on_create_failure" set to "keep") saves it to the state, so
+// Terraform marks it as tainted instead of losing its ID and URL.
+func (r *Resource) handleFailedEdit(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	id int,
+	data Model,
+	state *tfsdk.State,
+) {
+	if data.OnCreateFailure.ValueString() != onCreateFailureKeep {
 		r.deleteAfterFailedEdit(ctx, diags, id)
+
+		return
+	}
+
+	diags.AddWarning(
+		"CDN kept after failed edit",
+		fmt.Sprintf(
+			`CDN %d was created but its settings weren't applied; it's kept because "on_create_failure" is %q. `+
+				`Run "terraform untaint" to retry the edit on the next apply instead of replacing the CDN.`,
+			id,
+			onCreateFailureKeep,
+		),
+	)
+	r.removeIdempotencyMarker(ctx, diags, id, data)
+	diags.Append(state.Set(ctx, data)...)
+}
+
+// removeIdempotencyMarker restores the note of a CDN kept after a failed edit, which still contains the marker. It's
+// only a cleanup, so a failure is reported as a warning.
+func (r *Resource) removeIdempotencyMarker(ctx context.Context, diags *diag.Diagnostics, id int, data Model) {
+	const warnSummary = "Failed to remove idempotency marker from CDN note"
+
+	request := cdn77.CdnEditJSONRequestBody{Note: util.StringValueToNullable(data.Note)}
+
+	response, err := r.Client.CdnEditWithResponse(ctx, id, request)
+	if err != nil {
+		diags.AddWarning(warnSummary, err.Error())
+
+		return
+	}
+
+	var editDiags diag.Diagnostics
+	util.ProcessEmptyResponse(&editDiags, response, warnSummary, func() {})
+
+	for _, d := range editDiags.Errors() {
+		diags.AddWarning(d.Summary(), d.Detail())
 	}
 }
 
@@ -284,11 +558,15 @@ func (r *Resource) createEditRequest( //nolint:cyclop
 
 	if !data.SecureToken.Token.IsNull() {
 		request.SecureToken.Token = data.SecureToken.Token.ValueStringPointer()
+	} else if !data.SecureTokenWo.IsNull() {
+		request.SecureToken.Token = data.SecureTokenWo.ValueStringPointer()
 	}
 
 	request.SecureToken.Type = cdn77.SecureTokenType(data.SecureToken.Type.ValueString())
 
-	if !data.Ssl.SslId.IsNull() {
+	if data.Ssl.SslId.ValueString() == autoSslId {
+		request.Ssl.SslId = data.Ssl.ResolvedSslId.ValueStringPointer()
+	} else if !data.Ssl.SslId.IsNull() {
 		request.Ssl.SslId = data.Ssl.SslId.ValueStringPointer()
 	}
 
@@ -305,7 +583,7 @@ func (r *Resource) createEditRequest( //nolint:cyclop
 		}
 	}
 
-	return request, true
+	return withoutSections(diags, request, ignoredSections(ctx, diags, data.IgnoreSections))
 }
 
 func (*Resource) buildConditionalFeatures(
@@ -402,7 +680,11 @@ func parseConditionalFeaturesSecrets(
 	if secretAttr.IsNull() || secretAttr.IsUnknown() {
 		data.ConditionalFeatures.Secrets = types.MapNull(types.StringType)
 
-		return false, true
+		if data.ConditionalFeatures.SecretsWo.IsNull() {
+			return false, true
+		}
+
+		secretAttr = data.ConditionalFeatures.SecretsWo
 	}
 
 	elements := secretAttr.Elements()
@@ -431,6 +713,98 @@ func parseConditionalFeaturesSecrets(
 	return true, true
 }
 
+// readWriteOnlyValues copies values of write-only attributes from the configuration to the model, since they are
+// always null in the plan. The framework nullifies them again before the model is saved to the state.
+func readWriteOnlyValues(ctx context.Context, diags *diag.Diagnostics, config tfsdk.Config, data *Model) bool {
+	diags.Append(config.GetAttribute(ctx, path.Root("secure_token_wo"), &data.SecureTokenWo)...)
+
+	if data.ConditionalFeatures != nil {
+		secretsPath := path.Root("conditional_features").AtName("secrets_wo")
+		diags.Append(config.GetAttribute(ctx, secretsPath, &data.ConditionalFeatures.SecretsWo)...)
+	}
+
+	return !diags.HasError()
+}
+
+// withoutUnchangedWriteOnlyValues returns the model without the write-only values whose versions didn't change. The
+// values aren't in the state, so the sections containing them are sent only on create, when the version changes or
+// when another attribute of the section changes.
+func withoutUnchangedWriteOnlyValues(data Model, state Model) Model {
+	if data.SecureTokenWoVersion.Equal(state.SecureTokenWoVersion) {
+		data.SecureTokenWo = types.StringNull()
+	}
+
+	if data.ConditionalFeatures != nil {
+		stateVersion := types.Int64Null()
+		if state.ConditionalFeatures != nil {
+			stateVersion = state.ConditionalFeatures.SecretsWoVersion
+		}
+
+		conditionalFeatures := *data.ConditionalFeatures
+		if conditionalFeatures.SecretsWoVersion.Equal(stateVersion) {
+			conditionalFeatures.SecretsWo = types.MapNull(types.StringType)
+		}
+
+		data.ConditionalFeatures = &conditionalFeatures
+	}
+
+	return data
+}
+
+// withoutUnchangedSections removes the top-level sections (e.g. "cache") of the request that are the same in the
+// compared request (the request without unchanged write-only values) and in the request built from the state; the
+// result is nil if all sections are the same.
+func withoutUnchangedSections(
+	diags *diag.Diagnostics,
+	request cdn77.CdnEditJSONRequestBody,
+	comparedRequest cdn77.CdnEditJSONRequestBody,
+	stateRequest cdn77.CdnEditJSONRequestBody,
+) (*cdn77.CdnEditJSONRequestBody, bool) {
+	const errMessage = "Failed to compare CDN settings"
+
+	var sections, comparedSections, stateSections map[string]json.RawMessage
+
+	if err := util.JsonRoundTrip(request, &sections); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if err := util.JsonRoundTrip(comparedRequest, &comparedSections); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if err := util.JsonRoundTrip(stateRequest, &stateSections); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	for name := range sections {
+		comparedSection, inCompared := comparedSections[name]
+		stateSection, inState := stateSections[name]
+
+		if inCompared == inState && (!inState || util.JsonEqual(string(comparedSection), string(stateSection))) {
+			delete(sections, name)
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil, true
+	}
+
+	var result cdn77.CdnEditJSONRequestBody
+	if err := util.JsonRoundTrip(sections, &result); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	return &result, true
+}
+
 func (*Resource) createDefaultEditRequest() cdn77.CdnEditJSONRequestBody {
 	return cdn77.CdnEditJSONRequestBody{
 		Cache: &cdn77.Cache{
@@ -468,13 +842,15 @@ func (r *Resource) deleteAfterFailedEdit(ctx context.Context, diags *diag.Diagno
 	util.ValidateDeletionResponse(diags, response, errMessage)
 }
 
+// canonicalizeJSON pretty prints the JSON with sorted object keys, so the stored value is stable and plans show
+// line-level changes.
 func canonicalizeJSON(raw string) (string, error) {
 	var data any
 	if err := json.Unmarshal([]byte(raw), &data); err != nil {
 		return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
-	canonicalJSON, err := json.Marshal(data)
+	canonicalJSON, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal JSON: %w", err)
 	}
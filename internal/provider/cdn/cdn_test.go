@@ -3,8 +3,10 @@ package cdn_test
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"slices"
 	"sort"
@@ -15,8 +17,16 @@ import (
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
 	"github.com/cdn77/terraform-provider-cdn77/internal/acctest/testdata"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
 	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -906,6 +916,162 @@ resource "cdn77_cdn" "lorem" {
 	)
 }
 
+func TestAccCdnResource_ConditionalFeaturesRules(t *testing.T) {
+	const rsc = "cdn77_cdn.lorem"
+	client := acctest.GetClient(t)
+	var cdnId string
+
+	const rulesConfig = `
+resource "cdn77_cdn" "lorem" {
+  label     = "cdn with conditional feature rules"
+  origin_id = cdn77_origin_url.url.id
+
+  conditional_features = {
+    rule = [
+      {
+        if = {
+          operator   = "OR"
+          conditions = [
+            { type = "path-prefix", args = { prefix = "/atp" } },
+            { type = "var", args = { key = "set-cors", value = "true" } },
+            { type = "country", list_args = { country = ["CZ", "SK"] } },
+          ]
+        }
+        then = [
+          {
+            name   = "response_hdr"
+            config = jsonencode({ response_hdr_name = "Access-Control-Allow-Origin", response_hdr_value = "*" })
+          },
+        ]
+      },
+    ]
+  }
+}
+`
+
+	const configurationConfig = `
+resource "cdn77_cdn" "lorem" {
+  label     = "cdn with conditional feature rules"
+  origin_id = cdn77_origin_url.url.id
+
+  conditional_features = {
+    configuration = jsonencode([
+      {
+        if = [
+          "OR",
+          { type = "path-prefix", prefix = "/atp" },
+          { type = "var", key = "set-cors", value = "true" },
+          { type = "country", country = ["CZ", "SK"] },
+        ]
+        then = [
+          {
+            name   = "response_hdr"
+            config = { response_hdr_name = "Access-Control-Allow-Origin", response_hdr_value = "*" }
+          },
+        ]
+      },
+    ])
+  }
+}
+`
+
+	acctest.Run(t, checkCdnsAndOriginDestroyed(client),
+		resource.TestStep{
+			Config: OriginResourceConfig + rulesConfig,
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr(rsc, "id", &cdnId),
+				resource.TestCheckResourceAttr(rsc, "conditional_features.rule.#", "1"),
+				resource.TestCheckResourceAttr(rsc, "conditional_features.rule.0.if.operator", "OR"),
+				resource.TestCheckResourceAttr(rsc, "conditional_features.rule.0.if.conditions.#", "3"),
+				resource.TestCheckResourceAttr(
+					rsc,
+					"conditional_features.rule.0.if.conditions.2.list_args.country.#",
+					"2",
+				),
+				resource.TestCheckResourceAttr(rsc, "conditional_features.rule.0.then.0.name", "response_hdr"),
+				resource.TestCheckResourceAttrSet(rsc, "conditional_features.configuration"),
+				checkCdn(client, &cdnId, func(c *cdn77.Cdn) error {
+					configuration := *c.ConditionalFeatures.Configuration
+
+					return acctest.EqualField("conditional_features.configuration", len(configuration), 1)
+				}),
+			),
+		},
+		resource.TestStep{
+			Config:           OriginResourceConfig + configurationConfig,
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionNoop),
+		},
+		resource.TestStep{
+			Config:           OriginResourceConfig + rulesConfig,
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionNoop),
+		},
+	)
+}
+
+func TestAccCdnResource_WriteOnlySecrets(t *testing.T) {
+	const rsc = "cdn77_cdn.lorem"
+	client := acctest.GetClient(t)
+	var cdnId string
+
+	const cdnConfig = `
+resource "cdn77_cdn" "lorem" {
+  label     = "cdn with write-only secrets"
+  origin_id = cdn77_origin_url.url.id
+
+  secure_token = {
+    type = "parameter"
+  }
+  secure_token_wo         = "{token}"
+  secure_token_wo_version = {version}
+
+  conditional_features = {
+    configuration = jsonencode([
+      {
+        if   = [{ type = "path-prefix", prefix = "/Content/" }]
+        then = [{ name = "set_var", config = { key = "origin", value = "east" } }]
+      }
+    ])
+    secrets_wo         = { api_key = "{token}" }
+    secrets_wo_version = {version}
+  }
+}
+`
+
+	acctest.RunWriteOnly(t, checkCdnsAndOriginDestroyed(client),
+		resource.TestStep{
+			Config: OriginResourceConfig + acctest.Config(cdnConfig, "token", "abcd1234", "version", 1),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr(rsc, "id", &cdnId),
+				resource.TestCheckResourceAttr(rsc, "secure_token.type", string(cdn77.SecureTokenTypeParameter)),
+				resource.TestCheckResourceAttr(rsc, "secure_token_wo_version", "1"),
+				resource.TestCheckResourceAttr(rsc, "conditional_features.secrets_wo_version", "1"),
+				resource.TestCheckNoResourceAttr(rsc, "secure_token.token"),
+				resource.TestCheckNoResourceAttr(rsc, "secure_token_wo"),
+				resource.TestCheckNoResourceAttr(rsc, "conditional_features.secrets"),
+				resource.TestCheckNoResourceAttr(rsc, "conditional_features.secrets_wo"),
+				checkCdn(client, &cdnId, func(c *cdn77.Cdn) error {
+					return errors.Join(
+						acctest.EqualField("secure_token.type", c.SecureToken.Type, cdn77.SecureTokenTypeParameter),
+						acctest.EqualField("secure_token.token", *c.SecureToken.Token, "abcd1234"),
+					)
+				}),
+			),
+		},
+		resource.TestStep{
+			Config:           OriginResourceConfig + acctest.Config(cdnConfig, "token", "efgh5678", "version", 2),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionUpdate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAttr(rsc, "id", &cdnId),
+				resource.TestCheckResourceAttr(rsc, "secure_token_wo_version", "2"),
+				resource.TestCheckNoResourceAttr(rsc, "secure_token.token"),
+				checkCdn(client, &cdnId, func(c *cdn77.Cdn) error {
+					return acctest.EqualField("secure_token.token", *c.SecureToken.Token, "efgh5678")
+				}),
+			),
+		},
+	)
+}
+
 func TestAccCdnDataSource_OnlyRequiredFields(t *testing.T) {
 	const rsc = "data.cdn77_cdn.lorem"
 	const nonExistingCdnId = 7495732
@@ -1129,6 +1295,368 @@ func TestAccCdnDataSource_AllFields(t *testing.T) {
 	})
 }
 
+func TestResource_CreateFailedEdit(t *testing.T) {
+	testCases := map[string]struct {
+		onCreateFailure types.String
+		expectedEdits   int
+		expectedDeletes []int
+	}{
+		"deleted by default": {
+			onCreateFailure: types.StringNull(),
+			expectedEdits:   1,
+			expectedDeletes: []int{1},
+		},
+		"kept": {
+			onCreateFailure: types.StringValue("keep"),
+			expectedEdits:   2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var edits []cdn77.CdnEditJSONRequestBody
+			var deletes []int
+
+			r := &cdn.Resource{BaseResource: util.NewBaseResource("cdn", cdn.CreateResourceSchema, nil)}
+			r.Client = cdnClient{failEdits: true, edits: &edits, deletes: &deletes}
+
+			var data cdn.Model
+			if diags := cdnState(t, apiCdn()).Get(t.Context(), &data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			data.OnCreateFailure = tc.onCreateFailure
+
+			plan := tfsdk.Plan(cdnState(t, nil))
+			if diags := plan.Set(t.Context(), data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			resp := fwresource.CreateResponse{State: cdnState(t, nil)}
+			r.Create(t.Context(), fwresource.CreateRequest{Config: tfsdk.Config(plan), Plan: plan}, &resp)
+
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected the failed edit to be reported")
+			}
+
+			if !slices.Equal(deletes, tc.expectedDeletes) {
+				t.Errorf("expected deleted CDNs %v, got %v", tc.expectedDeletes, deletes)
+			}
+
+			if len(edits) != tc.expectedEdits {
+				t.Fatalf("expected %d edits, got %d", tc.expectedEdits, len(edits))
+			}
+
+			var id types.Int64
+			resp.Diagnostics.Append(resp.State.GetAttribute(t.Context(), path.Root("id"), &id)...)
+
+			if tc.expectedDeletes != nil {
+				if !resp.State.Raw.IsNull() {
+					t.Errorf("expected no state, got %s", resp.State.Raw)
+				}
+
+				return
+			}
+
+			if id.ValueInt64() != 1 {
+				t.Errorf("expected the kept CDN in the state, got ID %s", id)
+			}
+
+			if resp.Diagnostics.WarningsCount() != 1 {
+				t.Errorf("expected a warning about the kept CDN, got %v", resp.Diagnostics)
+			}
+
+			if cleanup := edits[1]; cleanup.Label != nil || cleanup.Note.MustGet() != data.Note.ValueString() {
+				t.Errorf("expected the idempotency marker to be removed from the note, got %+v", cleanup)
+			}
+		})
+	}
+}
+
+func TestResource_UpdateChangedSections(t *testing.T) {
+	withSecureTokenWo := func(data *cdn.Model) {
+		data.SecureToken = &cdn.ModelSecureToken{Token: types.StringNull(), Type: types.StringValue("parameter")}
+		data.SecureTokenWoVersion = types.Int64Value(1)
+	}
+	withSecretsWo := func(data *cdn.Model) {
+		data.ConditionalFeatures = &cdn.ModelConditionalFeatures{
+			Configuration:    util.NewJsonNull(),
+			Rule:             types.ListNull(conditionalFeaturesRuleType(t)),
+			Secrets:          types.MapNull(types.StringType),
+			SecretsWo:        types.MapNull(types.StringType),
+			SecretsWoVersion: types.Int64Value(1),
+		}
+	}
+
+	testCases := map[string]struct {
+		modifyState      func(data *cdn.Model)
+		modify           func(data *cdn.Model)
+		expectedSections string
+	}{
+		"nothing changed": {
+			modify: func(*cdn.Model) {},
+		},
+		"only attributes not sent to the API changed": {
+			modify: func(data *cdn.Model) {
+				data.ConditionalFeaturesLint = types.StringValue("off")
+			},
+		},
+		"changed section": {
+			modify: func(data *cdn.Model) {
+				data.Label = types.StringValue("new label")
+				data.Note = types.StringValue("new note")
+			},
+			expectedSections: `{"label":"new label","note":"new note"}`,
+		},
+		"removed note": {
+			modify: func(data *cdn.Model) {
+				data.Note = types.StringNull()
+			},
+			expectedSections: `{"note":null}`,
+		},
+		"defaulted section": {
+			modify: func(data *cdn.Model) {
+				data.HttpsRedirect = &cdn.ModelHttpsRedirect{Code: types.Int64Null(), Enabled: types.BoolValue(false)}
+			},
+			expectedSections: `{"https_redirect":{"enabled":false}}`,
+		},
+		"write-only secure token with unchanged version": {
+			modifyState: withSecureTokenWo,
+			modify: func(data *cdn.Model) {
+				data.SecureTokenWo = types.StringValue("token")
+			},
+		},
+		"write-only secure token with changed version": {
+			modifyState: withSecureTokenWo,
+			modify: func(data *cdn.Model) {
+				data.SecureTokenWo = types.StringValue("new token")
+				data.SecureTokenWoVersion = types.Int64Value(2)
+			},
+			expectedSections: `{"secure_token":{"token":"new token","type":"parameter"}}`,
+		},
+		"write-only secure token with changed type": {
+			modifyState: withSecureTokenWo,
+			modify: func(data *cdn.Model) {
+				data.SecureToken.Type = types.StringValue("path")
+				data.SecureTokenWo = types.StringValue("token")
+			},
+			expectedSections: `{"secure_token":{"token":"token","type":"path"}}`,
+		},
+		"write-only secrets with unchanged version": {
+			modifyState: withSecretsWo,
+			modify: func(data *cdn.Model) {
+				data.ConditionalFeatures.SecretsWo = stringMap("token", "secret")
+			},
+		},
+		"write-only secrets with changed version": {
+			modifyState: withSecretsWo,
+			modify: func(data *cdn.Model) {
+				data.ConditionalFeatures.SecretsWo = stringMap("token", "new secret")
+				data.ConditionalFeatures.SecretsWoVersion = types.Int64Value(2)
+			},
+			expectedSections: `{"conditional_features":{"secrets":{"token":"new secret"}}}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var edits []cdn77.CdnEditJSONRequestBody
+
+			r := &cdn.Resource{BaseResource: util.NewBaseResource("cdn", cdn.CreateResourceSchema, nil)}
+			r.Client = cdnClient{edits: &edits}
+
+			state := cdnState(t, apiCdn())
+
+			var data cdn.Model
+			if diags := state.Get(t.Context(), &data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if tc.modifyState != nil {
+				tc.modifyState(&data)
+
+				if diags := state.Set(t.Context(), data); diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+			}
+
+			tc.modify(&data)
+
+			plan := tfsdk.Plan(cdnState(t, nil))
+			if diags := plan.Set(t.Context(), data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			req := fwresource.UpdateRequest{Config: tfsdk.Config(plan), Plan: plan, State: state}
+			resp := fwresource.UpdateResponse{State: state}
+			r.Update(t.Context(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			if tc.expectedSections == "" {
+				if len(edits) != 0 {
+					t.Fatalf("expected no API call, got %v", edits)
+				}
+
+				return
+			}
+
+			if len(edits) != 1 {
+				t.Fatalf("expected 1 edit, got %d", len(edits))
+			}
+
+			raw, err := json.Marshal(edits[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !util.JsonEqual(string(raw), tc.expectedSections) {
+				t.Errorf("expected request %s, got %s", tc.expectedSections, raw)
+			}
+		})
+	}
+}
+
+func conditionalFeaturesRuleType(t *testing.T) attr.Type {
+	t.Helper()
+
+	rulePath := path.Root("conditional_features").AtName("rule")
+
+	rule, diags := cdn.CreateResourceSchema().AttributeAtPath(t.Context(), rulePath)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	listType, ok := rule.GetType().(types.ListType)
+	if !ok {
+		t.Fatalf("unexpected type of %s: %s", rulePath, rule.GetType())
+	}
+
+	return listType.ElemType
+}
+
+// cdnClient serves the given CDN and records the edit and delete requests; if failEdits is set, edits of the CDN
+// settings fail and only the note-only edits succeed.
+type cdnClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	cdn       *cdn77.Cdn
+	failEdits bool
+	edits     *[]cdn77.CdnEditJSONRequestBody
+	deletes   *[]int
+}
+
+func (cdnClient) CdnAddWithResponse(
+	_ context.Context,
+	body cdn77.CdnAddJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnAddResponse, error) {
+	return &cdn77.CdnAddResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+		JSON201:      &cdn77.CdnSummary{Id: 1, Label: body.Label, Note: body.Note, Url: "https://1.rsc.cdn77.org"},
+	}, nil
+}
+
+func (c cdnClient) CdnDetailWithResponse(
+	context.Context,
+	int,
+	...cdn77.RequestEditorFn,
+) (*cdn77.CdnDetailResponse, error) {
+	if c.cdn == nil {
+		return &cdn77.CdnDetailResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil
+	}
+
+	return &cdn77.CdnDetailResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: c.cdn}, nil
+}
+
+func (c cdnClient) CdnEditWithResponse(
+	_ context.Context,
+	_ int,
+	body cdn77.CdnEditJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnEditResponse, error) {
+	*c.edits = append(*c.edits, body)
+
+	if c.failEdits && body.Label != nil {
+		return &cdn77.CdnEditResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusUnprocessableEntity},
+			JSON422:      &cdn77.FieldErrors{Errors: []string{"invalid settings"}},
+		}, nil
+	}
+
+	return &cdn77.CdnEditResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+func (c cdnClient) CdnDeleteWithResponse(
+	_ context.Context,
+	id int,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnDeleteResponse, error) {
+	*c.deletes = append(*c.deletes, id)
+
+	return &cdn77.CdnDeleteResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+// apiCdn returns a CDN as returned by the API with the default settings.
+func apiCdn() *cdn77.Cdn {
+	return &cdn77.Cdn{
+		Id:       1,
+		Label:    "some cdn",
+		Url:      "https://1234567890.rsc.cdn77.org",
+		OriginId: nullable.NewNullableWithValue("origin-id"),
+		Cnames:   cdn77.Cnames{},
+		Cache: &cdn77.Cache{
+			MaxAge:                     util.Pointer(cdn77.N17280),
+			MaxAge404:                  nullable.NewNullNullable[cdn77.MaxAge404](),
+			RequestsWithCookiesEnabled: util.Pointer(true),
+		},
+		GeoProtection: &cdn77.GeoProtection{Type: cdn77.Disabled},
+		Headers: &cdn77.Headers{
+			ContentDisposition: &cdn77.ContentDisposition{
+				Type: util.Pointer(cdn77.ContentDispositionTypeNone),
+			},
+			CorsEnabled:                 util.Pointer(false),
+			CorsTimingEnabled:           util.Pointer(false),
+			CorsWildcardEnabled:         util.Pointer(false),
+			HostHeaderForwardingEnabled: util.Pointer(false),
+		},
+		HotlinkProtection:  &cdn77.HotlinkProtection{Type: cdn77.Disabled},
+		HttpsRedirect:      &cdn77.HttpsRedirect{Enabled: true, Code: util.Pointer(cdn77.N301)},
+		IpProtection:       &cdn77.IpProtection{Type: cdn77.Disabled},
+		Mp4PseudoStreaming: &cdn77.Mp4PseudoStreaming{Enabled: util.Pointer(false)},
+		Note:               nullable.NewNullableWithValue("some note"),
+		OriginHeaders:      &cdn77.OriginHeaders{Custom: nullable.NewNullNullable[map[string]string]()},
+		QueryString:        &cdn77.QueryString{IgnoreType: cdn77.QueryStringIgnoreTypeNone},
+		RateLimit:          &cdn77.RateLimit{},
+		SecureToken:        &cdn77.SecureToken{Type: cdn77.SecureTokenTypeNone},
+		Ssl:                &cdn77.CdnSsl{Type: cdn77.InstantSsl},
+	}
+}
+
+// cdnState returns the state of the CDN read from the API (or a null state if the CDN is nil).
+func cdnState(t *testing.T, c *cdn77.Cdn) tfsdk.State {
+	t.Helper()
+
+	s := cdn.CreateResourceSchema()
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+
+	if c == nil {
+		return state
+	}
+
+	var diags diag.Diagnostics
+	data := (&cdn.Reader{}).Process(t.Context(), cdn.Model{}, c, &diags)
+	diags.Append(state.Set(t.Context(), data)...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	return state
+}
+
 func checkCdn(
 	client cdn77.ClientWithResponsesInterface,
 	cdnId *string,
@@ -0,0 +1,263 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithConfigure   = &CnameResource{}
+	_ resource.ResourceWithImportState = &CnameResource{}
+)
+
+type CnameResource struct {
+	*util.BaseResource
+}
+
+func (r *CnameResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	diags := &resp.Diagnostics
+	var data CnameModel
+
+	if diags.Append(req.Plan.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(cnameId(data.CdnId.ValueInt64(), data.Cname.ValueString()))
+
+	const errMessage = "Failed to assign CNAME to CDN"
+
+	modify := func(cnames []string) ([]string, bool) {
+		if containsCname(cnames, data.Cname.ValueString()) {
+			diags.AddAttributeError(
+				path.Root("cname"),
+				errMessage,
+				fmt.Sprintf("CNAME %q is already assigned to the CDN", data.Cname.ValueString()),
+			)
+
+			return nil, false
+		}
+
+		return append(cnames, data.Cname.ValueString()), true
+	}
+
+	if r.editCnames(ctx, diags, errMessage, data.CdnId.ValueInt64(), false, modify) {
+		diags.Append(resp.State.Set(ctx, data)...)
+	}
+}
+
+func (r *CnameResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	diags := &resp.Diagnostics
+	var data CnameModel
+
+	if diags.Append(req.State.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	cnames, ok := fetchCnames(ctx, r.Client, diags, data.CdnId.ValueInt64())
+	if !ok {
+		return
+	}
+
+	i := slices.IndexFunc(cnames, func(cname string) bool {
+		return strings.EqualFold(cname, data.Cname.ValueString())
+	})
+	if i == -1 {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	data.Cname = types.StringValue(cnames[i])
+
+	diags.Append(resp.State.Set(ctx, data)...)
+}
+
+func (*CnameResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, so there is nothing to update.
+	var data CnameModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *CnameResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	diags := &resp.Diagnostics
+	var data CnameModel
+
+	if diags.Append(req.State.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	const errMessage = "Failed to remove CNAME from CDN"
+
+	modify := func(cnames []string) ([]string, bool) {
+		return slices.DeleteFunc(cnames, func(cname string) bool {
+			return strings.EqualFold(cname, data.Cname.ValueString())
+		}), true
+	}
+
+	r.editCnames(ctx, diags, errMessage, data.CdnId.ValueInt64(), true, modify)
+}
+
+func (*CnameResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	cdnIdPart, cname, found := strings.Cut(req.ID, "/")
+	cdnId, err := strconv.ParseInt(cdnIdPart, 10, 64)
+
+	if !found || err != nil || cname == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: <cdn_id>/<cname>, got: %q", req.ID),
+		)
+
+		return
+	}
+
+	diags := &resp.Diagnostics
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("id"), cnameId(cdnId, cname))...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("cdn_id"), cdnId)...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("cname"), cname)...)
+}
+
+// editCnames modifies the CNAMEs of the CDN while holding the lock of its CNAMEs, so CNAMEs of the same CDN can be
+// assigned in parallel. Nothing is done if the CDN doesn't exist anymore and the CNAME is being removed.
+func (r *CnameResource) editCnames(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	errMessage string,
+	cdnId int64,
+	deleting bool,
+	modify func(cnames []string) ([]string, bool),
+) bool {
+	defer lockCdn(cdnId, cdnSectionCnames)()
+
+	cnames, ok := fetchCnames(ctx, r.Client, diags, cdnId)
+	if !ok {
+		return false
+	}
+
+	if cnames == nil {
+		if deleting {
+			return true
+		}
+
+		diags.AddAttributeError(path.Root("cdn_id"), errMessage, fmt.Sprintf("CDN %d not found", cdnId))
+
+		return false
+	}
+
+	if cnames, ok = modify(cnames); !ok {
+		return false
+	}
+
+	response, err := r.Client.CdnEditWithResponse(ctx, int(cdnId), cdn77.CdnEditJSONRequestBody{Cnames: &cnames})
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return false
+	}
+
+	util.ProcessEmptyResponse(diags, response, errMessage, func() {})
+
+	return !diags.HasError()
+}
+
+// fetchCnames returns the CNAMEs of the CDN; the result is nil (without any error) if the CDN doesn't exist.
+func fetchCnames(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	cdnId int64,
+) ([]string, bool) {
+	const errMessage = "Failed to fetch CNAMEs of CDN"
+
+	response, err := client.CnameListWithResponse(ctx, int(cdnId))
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		return nil, true
+	}
+
+	cnames := []string{}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *cdn77.Cnames) {
+		for _, c := range *list {
+			cnames = append(cnames, c.Cname)
+		}
+	})
+
+	return cnames, !diags.HasError()
+}
+
+// keepExternalCnames adds the CNAMEs unknown to the CDN resource to the edited CNAMEs, so the edit doesn't remove
+// them. CNAMEs listed in ownCnames (the CNAMEs previously set by the CDN resource) are removed unless they are edited.
+func keepExternalCnames(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	cdnId int64,
+	ownCnames []string,
+	request *cdn77.CdnEditJSONRequestBody,
+) bool {
+	current, ok := fetchCnames(ctx, client, diags, cdnId)
+	if !ok || current == nil {
+		return ok
+	}
+
+	cnames := []string{}
+	if request.Cnames != nil {
+		cnames = *request.Cnames
+	}
+
+	for _, cname := range current {
+		if !containsCname(ownCnames, cname) && !containsCname(cnames, cname) {
+			cnames = append(cnames, cname)
+		}
+	}
+
+	request.Cnames = &cnames
+
+	return true
+}
+
+// ownCnames returns the CNAMEs set by the CDN resource.
+func ownCnames(data Model) []string {
+	cnames := make([]string, 0, len(data.Cnames.Elements()))
+
+	for _, value := range data.Cnames.Elements() {
+		if cname, ok := value.(types.String); ok {
+			cnames = append(cnames, cname.ValueString())
+		}
+	}
+
+	return cnames
+}
+
+func containsCname(cnames []string, cname string) bool {
+	return slices.ContainsFunc(cnames, func(c string) bool { return strings.EqualFold(c, cname) })
+}
+
+func cnameId(cdnId int64, cname string) string {
+	return fmt.Sprintf("%d/%s", cdnId, cname)
+}
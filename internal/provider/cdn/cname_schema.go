@@ -0,0 +1,45 @@
+package cdn
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CnameModel struct {
+	Id    types.String `tfsdk:"id"`
+	CdnId types.Int64  `tfsdk:"cdn_id"`
+	Cname types.String `tfsdk:"cname"`
+}
+
+func CreateCnameResourceSchema() schema.Schema {
+	return schema.Schema{
+		Description: "CDN CNAME resource assigns a single CNAME to a CDN Resource, so the CNAMEs of a shared CDN can " +
+			`be owned by different teams. Set "ignore_external_cnames" of the "cdn77_cdn" resource to true, ` +
+			"otherwise the CDN resource removes the CNAME on its next update.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "ID of the CNAME assignment in the format <cdn_id>/<cname>",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"cdn_id": schema.Int64Attribute{
+				Required:      true,
+				Description:   "ID of the CDN Resource the CNAME is assigned to",
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"cname": schema.StringAttribute{
+				Required: true,
+				Description: "CNAME assigned to the CDN. " +
+					"CNAME should be mapped via DNS to CDN URL. " +
+					"Otherwise it's not possible to generate an SSL certificate for the CNAME.",
+				Validators:    []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+		},
+	}
+}
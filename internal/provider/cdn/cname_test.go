@@ -0,0 +1,78 @@
+package cdn_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCdnCnameResource(t *testing.T) {
+	const rsc = "cdn77_cdn_cname.shop"
+	client := acctest.GetClient(t)
+	var cdnId string
+
+	config := func(label string) string {
+		return OriginResourceConfig + fmt.Sprintf(`
+resource "cdn77_cdn" "lorem" {
+  label     = %q
+  origin_id = cdn77_origin_url.url.id
+  cnames    = ["my-cdn.example.com"]
+
+  ignore_external_cnames = true
+}
+
+resource "cdn77_cdn_cname" "shop" {
+  cdn_id = cdn77_cdn.lorem.id
+  cname  = "my-shop-cdn.example.com"
+}
+
+resource "cdn77_cdn_cname" "blog" {
+  cdn_id = cdn77_cdn.lorem.id
+  cname  = "my-blog-cdn.example.com"
+}
+`, label)
+	}
+
+	allCnames := []string{"my-blog-cdn.example.com", "my-cdn.example.com", "my-shop-cdn.example.com"}
+
+	acctest.Run(t, checkCdnsAndOriginDestroyed(client),
+		resource.TestStep{
+			Config: config("cdn with cname resources"),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr("cdn77_cdn.lorem", "id", &cdnId),
+				resource.TestCheckResourceAttr("cdn77_cdn.lorem", "cnames.#", "1"),
+				resource.TestCheckResourceAttr(rsc, "cname", "my-shop-cdn.example.com"),
+				checkCdn(client, &cdnId, checkCnames(allCnames...)),
+			),
+		},
+		resource.TestStep{
+			Config: config("cdn with updated cname resources"),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr("cdn77_cdn.lorem", "cnames.#", "1"),
+				checkCdn(client, &cdnId, checkCnames(allCnames...)),
+			),
+		},
+		resource.TestStep{
+			ResourceName:      rsc,
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	)
+}
+
+func checkCnames(expected ...string) func(c *cdn77.Cdn) error {
+	return func(c *cdn77.Cdn) error {
+		cnames := make([]string, len(c.Cnames))
+		for i, cname := range c.Cnames {
+			cnames[i] = cname.Cname
+		}
+
+		slices.Sort(cnames)
+
+		return acctest.EqualField("cnames", fmt.Sprint(cnames), fmt.Sprint(expected))
+	}
+}
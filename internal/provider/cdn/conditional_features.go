@@ -0,0 +1,499 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	ErrConditionalFeaturesNestedConditions = errors.New("nested condition groups can't be expressed as rules")
+	ErrConditionalFeaturesConditionType    = errors.New("condition type must be a string")
+	ErrConditionalFeaturesConditionArg     = errors.New("condition arguments must be strings or arrays of strings")
+	ErrConditionalFeaturesDuplicateArg     = errors.New(`condition argument is set in both "args" and "list_args"`)
+)
+
+type jsonRule struct {
+	If   *[]json.RawMessage `json:"if,omitempty"`
+	Then *[]jsonAction      `json:"then,omitempty"`
+}
+
+type jsonAction struct {
+	Name   *string          `json:"name,omitempty"`
+	Config *json.RawMessage `json:"config,omitempty"`
+}
+
+func conditionOperators() []string {
+	return []string{"AND", "NAND", "NOR", "OR"}
+}
+
+func conditionalFeaturesRuleType() types.ObjectType {
+	return types.ObjectType{AttrTypes: map[string]attr.Type{
+		"if": types.ObjectType{AttrTypes: map[string]attr.Type{
+			"operator": types.StringType,
+			"conditions": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+				"type":      types.StringType,
+				"args":      types.MapType{ElemType: types.StringType},
+				"list_args": types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+			}}},
+		}},
+		"then": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"name":   types.StringType,
+			"config": util.JsonType{},
+		}}},
+	}}
+}
+
+// canonicalConfiguration returns the configuration in the same form as the one read from the API, so the values
+// computed from rules and from the API response are comparable.
+func canonicalConfiguration(raw []byte) (string, error) {
+	normalized, err := normalizeConditionalFeaturesEmptyConfigArray(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return canonicalizeJSON(string(normalized))
+}
+
+// ruleListFromConfiguration converts the JSON configuration to rules. The result is null if the configuration
+// can't be expressed as rules (e.g. it is invalid, contains nested condition groups or condition arguments which are
+// neither strings nor arrays of strings).
+func ruleListFromConfiguration(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	configuration util.JsonValue,
+) types.List {
+	ruleType := conditionalFeaturesRuleType()
+
+	if configuration.IsNull() || configuration.IsUnknown() {
+		return types.ListNull(ruleType)
+	}
+
+	canonical, err := canonicalConfiguration([]byte(configuration.ValueString()))
+	if err != nil {
+		return types.ListNull(ruleType)
+	}
+
+	rules, err := rulesFromConfiguration(canonical)
+	if err != nil {
+		return types.ListNull(ruleType)
+	}
+
+	list, listDiags := types.ListValueFrom(ctx, ruleType, rules)
+	diags.Append(listDiags...)
+
+	return list
+}
+
+func rulesFromConfiguration(configuration string) ([]ModelConditionalFeaturesRule, error) {
+	// The raw JSON values (e.g. action configs) are copied to the rules as they are, so they must be compact.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(configuration)); err != nil {
+		return nil, err
+	}
+
+	var jsonRules []jsonRule
+	if err := json.Unmarshal(compact.Bytes(), &jsonRules); err != nil {
+		return nil, err
+	}
+
+	rules := make([]ModelConditionalFeaturesRule, len(jsonRules))
+
+	for i, jr := range jsonRules {
+		rule, err := ruleFromJson(jr)
+		if err != nil {
+			return nil, fmt.Errorf("rule #%d: %w", i, err)
+		}
+
+		rules[i] = rule
+	}
+
+	return rules, nil
+}
+
+// ruleFromJson converts a single rule; raw JSON values of the rule must be compact.
+func ruleFromJson(jr jsonRule) (ModelConditionalFeaturesRule, error) {
+	var rule ModelConditionalFeaturesRule
+
+	if jr.If != nil {
+		ruleIf, err := conditionsFromJson(*jr.If)
+		if err != nil {
+			return ModelConditionalFeaturesRule{}, err
+		}
+
+		rule.If = ruleIf
+	}
+
+	if jr.Then != nil {
+		rule.Then = make([]ModelConditionalFeaturesAction, len(*jr.Then))
+
+		for i, action := range *jr.Then {
+			rule.Then[i] = ModelConditionalFeaturesAction{
+				Name:   types.StringPointerValue(action.Name),
+				Config: util.NewJsonNull(),
+			}
+
+			if action.Config != nil {
+				rule.Then[i].Config = util.NewJsonValue(string(*action.Config))
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+func conditionsFromJson(items []json.RawMessage) (*ModelConditionalFeaturesIf, error) {
+	ruleIf := &ModelConditionalFeaturesIf{
+		Operator:   types.StringNull(),
+		Conditions: make([]ModelConditionalFeaturesCondition, 0, len(items)),
+	}
+
+	for i, item := range items {
+		var operator string
+		if i == 0 && json.Unmarshal(item, &operator) == nil {
+			ruleIf.Operator = types.StringValue(operator)
+
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(item, &fields); err != nil || fields == nil {
+			return nil, ErrConditionalFeaturesNestedConditions
+		}
+
+		condition := ModelConditionalFeaturesCondition{Type: types.StringNull()}
+
+		if rawType, ok := fields["type"]; ok {
+			var conditionType string
+			if err := json.Unmarshal(rawType, &conditionType); err != nil {
+				return nil, ErrConditionalFeaturesConditionType
+			}
+
+			condition.Type = types.StringValue(conditionType)
+
+			delete(fields, "type")
+		}
+
+		args, listArgs, err := conditionArgsFromJson(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		condition.Args = args
+		condition.ListArgs = listArgs
+		ruleIf.Conditions = append(ruleIf.Conditions, condition)
+	}
+
+	return ruleIf, nil
+}
+
+// configurationFromRuleList converts rules to the canonical JSON configuration; the rules must be fully known.
+func configurationFromRuleList(ctx context.Context, diags *diag.Diagnostics, ruleList types.List) (string, bool) {
+	var rules []ModelConditionalFeaturesRule
+	if diags.Append(ruleList.ElementsAs(ctx, &rules, false)...); diags.HasError() {
+		return "", false
+	}
+
+	jsonRules := make([]jsonRule, len(rules))
+
+	for i, rule := range rules {
+		jr, err := ruleToJson(rule)
+		if err != nil {
+			diags.AddError("Failed to convert conditional feature rules", err.Error())
+
+			return "", false
+		}
+
+		jsonRules[i] = jr
+	}
+
+	raw, err := json.Marshal(jsonRules)
+	if err != nil {
+		diags.AddError("Failed to convert conditional feature rules", err.Error())
+
+		return "", false
+	}
+
+	configuration, err := canonicalConfiguration(raw)
+	if err != nil {
+		diags.AddError("Failed to canonicalize conditional feature rules", err.Error())
+
+		return "", false
+	}
+
+	return configuration, true
+}
+
+func ruleToJson(rule ModelConditionalFeaturesRule) (jsonRule, error) {
+	var jr jsonRule
+
+	if rule.If != nil {
+		items, err := conditionsToJson(rule.If)
+		if err != nil {
+			return jsonRule{}, err
+		}
+
+		jr.If = &items
+	}
+
+	if rule.Then != nil {
+		actions := make([]jsonAction, len(rule.Then))
+
+		for i, action := range rule.Then {
+			actions[i].Name = action.Name.ValueStringPointer()
+
+			if !action.Config.IsNull() {
+				config := json.RawMessage(action.Config.ValueString())
+				actions[i].Config = &config
+			}
+		}
+
+		jr.Then = &actions
+	}
+
+	return jr, nil
+}
+
+func conditionsToJson(ruleIf *ModelConditionalFeaturesIf) ([]json.RawMessage, error) {
+	items := make([]json.RawMessage, 0, len(ruleIf.Conditions)+1)
+
+	if !ruleIf.Operator.IsNull() {
+		operator, err := json.Marshal(ruleIf.Operator.ValueString())
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, operator)
+	}
+
+	for _, condition := range ruleIf.Conditions {
+		fields, err := conditionArgsToJson(condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if !condition.Type.IsNull() {
+			conditionType, err := json.Marshal(condition.Type.ValueString())
+			if err != nil {
+				return nil, err
+			}
+
+			fields["type"] = conditionType
+		}
+
+		item, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// conditionArgsFromJson splits the arguments of a condition to the string ones ("args") and the ones which are arrays
+// of strings ("list_args"); both maps are null if there is no such argument.
+func conditionArgsFromJson(fields map[string]json.RawMessage) (types.Map, types.Map, error) {
+	args := make(map[string]attr.Value, len(fields))
+	listArgs := make(map[string]attr.Value, len(fields))
+
+	for name, rawValue := range fields {
+		var value any
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			return types.Map{}, types.Map{}, err
+		}
+
+		switch value := value.(type) {
+		case string:
+			args[name] = types.StringValue(value)
+		case []any:
+			elements := make([]attr.Value, len(value))
+
+			for i, element := range value {
+				s, ok := element.(string)
+				if !ok {
+					return types.Map{}, types.Map{}, fmt.Errorf("%w: %q", ErrConditionalFeaturesConditionArg, name)
+				}
+
+				elements[i] = types.StringValue(s)
+			}
+
+			listArgs[name] = types.ListValueMust(types.StringType, elements)
+		default:
+			return types.Map{}, types.Map{}, fmt.Errorf("%w: %q", ErrConditionalFeaturesConditionArg, name)
+		}
+	}
+
+	listType := types.ListType{ElemType: types.StringType}
+
+	return conditionArgMap(types.StringType, args), conditionArgMap(listType, listArgs), nil
+}
+
+func conditionArgMap(elemType attr.Type, values map[string]attr.Value) types.Map {
+	if len(values) == 0 {
+		return types.MapNull(elemType)
+	}
+
+	return types.MapValueMust(elemType, values)
+}
+
+// conditionArgsToJson is the inverse of conditionArgsFromJson; the condition type isn't included.
+func conditionArgsToJson(condition ModelConditionalFeaturesCondition) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage, len(condition.Args.Elements())+len(condition.ListArgs.Elements())+1)
+
+	for name, value := range condition.Args.Elements() {
+		if s, ok := value.(types.String); ok && !s.IsNull() {
+			arg, err := json.Marshal(s.ValueString())
+			if err != nil {
+				return nil, err
+			}
+
+			fields[name] = arg
+		}
+	}
+
+	for name, value := range condition.ListArgs.Elements() {
+		list, ok := value.(types.List)
+		if !ok || list.IsNull() {
+			continue
+		}
+
+		if _, ok := fields[name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrConditionalFeaturesDuplicateArg, name)
+		}
+
+		elements := make([]string, 0, len(list.Elements()))
+
+		for _, element := range list.Elements() {
+			if s, ok := element.(types.String); ok && !s.IsNull() {
+				elements = append(elements, s.ValueString())
+			}
+		}
+
+		arg, err := json.Marshal(elements)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[name] = arg
+	}
+
+	return fields, nil
+}
+
+func isFullyKnown(ctx context.Context, value attr.Value) bool {
+	tfValue, err := value.ToTerraformValue(ctx)
+
+	return err == nil && tfValue.IsFullyKnown()
+}
+
+// ConfigurationAndRulePlanModifier computes "configuration" from "rule" and vice versa, so both attributes
+// always describe the same rules no matter which one is configured.
+type ConfigurationAndRulePlanModifier struct{}
+
+func (ConfigurationAndRulePlanModifier) Description(context.Context) string {
+	return `sets "configuration" if "rule" is set and vice versa`
+}
+
+func (m ConfigurationAndRulePlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (ConfigurationAndRulePlanModifier) PlanModifyString(
+	ctx context.Context,
+	req planmodifier.StringRequest,
+	resp *planmodifier.StringResponse,
+) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	var rules types.List
+
+	if diags.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName("rule"), &rules)...); diags.HasError() {
+		return
+	}
+
+	switch {
+	case rules.IsNull():
+		resp.PlanValue = types.StringNull()
+	case !isFullyKnown(ctx, rules):
+		resp.PlanValue = types.StringUnknown()
+	default:
+		configuration, ok := configurationFromRuleList(ctx, diags, rules)
+		if !ok {
+			return
+		}
+
+		// The framework doesn't apply semantic equality to plans, so it's done here to avoid diffs in formatting.
+		if !req.StateValue.IsNull() && util.JsonEqual(req.StateValue.ValueString(), configuration) {
+			resp.PlanValue = req.StateValue
+
+			return
+		}
+
+		resp.PlanValue = types.StringValue(configuration)
+	}
+}
+
+func (ConfigurationAndRulePlanModifier) PlanModifyList(
+	ctx context.Context,
+	req planmodifier.ListRequest,
+	resp *planmodifier.ListResponse,
+) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	var configuration util.JsonValue
+	configurationPath := req.Path.ParentPath().AtName("configuration")
+
+	if diags.Append(req.Config.GetAttribute(ctx, configurationPath, &configuration)...); diags.HasError() {
+		return
+	}
+
+	if configuration.IsUnknown() {
+		resp.PlanValue = types.ListUnknown(conditionalFeaturesRuleType())
+
+		return
+	}
+
+	resp.PlanValue = ruleListFromConfiguration(ctx, diags, configuration)
+}
+
+type jsonObjectValidator struct{}
+
+func (jsonObjectValidator) Description(context.Context) string {
+	return "value must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (jsonObjectValidator) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &object); err != nil || object == nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON", "Value must be a JSON object; use jsonencode()")
+	}
+}
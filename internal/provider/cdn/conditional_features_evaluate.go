@@ -0,0 +1,227 @@
+package cdn
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ConditionalFeaturesEvaluateModel struct {
+	Configuration util.JsonValue                           `tfsdk:"configuration"`
+	Request       ConditionalFeaturesEvaluateRequestModel  `tfsdk:"request"`
+	MatchedRules  []int64                                  `tfsdk:"matched_rules"`
+	Actions       []ConditionalFeaturesEvaluateActionModel `tfsdk:"actions"`
+	Variables     map[string]string                        `tfsdk:"variables"`
+}
+
+type ConditionalFeaturesEvaluateRequestModel struct {
+	Path     types.String `tfsdk:"path"`
+	Query    types.Map    `tfsdk:"query"`
+	Headers  types.Map    `tfsdk:"headers"`
+	Country  types.String `tfsdk:"country"`
+	ClientIp types.String `tfsdk:"client_ip"`
+	Scheme   types.String `tfsdk:"scheme"`
+}
+
+type ConditionalFeaturesEvaluateActionModel struct {
+	Rule   types.Int64  `tfsdk:"rule"`
+	Name   types.String `tfsdk:"name"`
+	Config types.String `tfsdk:"config"`
+}
+
+var _ datasource.DataSource = &ConditionalFeaturesEvaluateDataSource{}
+
+type ConditionalFeaturesEvaluateDataSource struct{}
+
+func NewConditionalFeaturesEvaluateDataSource() datasource.DataSource {
+	return &ConditionalFeaturesEvaluateDataSource{}
+}
+
+func (*ConditionalFeaturesEvaluateDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "conditional_features_evaluate"}, "_")
+}
+
+func (*ConditionalFeaturesEvaluateDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"configuration": schema.StringAttribute{
+				CustomType: util.JsonType{},
+				Required:   true,
+				Description: "JSON configuration of conditional features " +
+					`(e.g. "conditional_features.configuration" of a CDN)`,
+				Validators: []validator.String{ConditionalFeaturesConfigurationValidator{}},
+			},
+			"request": schema.SingleNestedAttribute{
+				Required:    true,
+				Description: "Synthetic request the rules are evaluated for",
+				Attributes: map[string]schema.Attribute{
+					"path": schema.StringAttribute{
+						Required:    true,
+						Description: "Path of the request (including the leading slash, without the query string)",
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(absolutePathRegexp, "must start with /"),
+						},
+					},
+					"query": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Query string parameters of the request",
+					},
+					"headers": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Headers of the request; header names are case insensitive",
+					},
+					"country": schema.StringAttribute{
+						Optional:    true,
+						Description: "ISO 3166-1 alpha-2 code of the country the request comes from",
+						Validators:  []validator.String{stringvalidator.LengthBetween(2, 2)},
+					},
+					"client_ip": schema.StringAttribute{
+						Optional:    true,
+						Description: "IP address of the client",
+					},
+					"scheme": schema.StringAttribute{
+						Optional:    true,
+						Description: `Scheme of the request; either "http" or "https" (default)`,
+						Validators:  []validator.String{stringvalidator.OneOf("http", "https")},
+					},
+				},
+			},
+			"matched_rules": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: "Zero-based indexes of the rules whose conditions match the request",
+			},
+			"actions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Actions of the matched rules in the order they are applied",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"rule": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Zero-based index of the rule the action belongs to",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the feature",
+						},
+						"config": schema.StringAttribute{
+							Computed:    true,
+							Description: "JSON configuration of the feature; null if the action has no configuration",
+						},
+					},
+				},
+			},
+			"variables": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: `Variables set by the "set_var" actions of the matched rules`,
+			},
+		},
+		Description: "Conditional features evaluate data source evaluates conditional feature rules locally " +
+			"(without calling the API) for a synthetic request, so you can check which rules match before " +
+			"applying the configuration. Supported condition types (with their arguments) are " +
+			`"path-prefix" ("prefix"), "path-suffix" ("suffix"), "scheme" ("scheme"), "country" ("country"), ` +
+			`"client-ip" ("ip", addresses or CIDRs), "query-param" ("key" and optional "value"), ` +
+			`"header" ("name" and optional "value") and "var" ("key" and optional "value"); arguments can be ` +
+			"strings or arrays of strings (any of the values matches). Other condition types result in an error.",
+	}
+}
+
+func (*ConditionalFeaturesEvaluateDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	const errMessage = "Failed to evaluate conditional features"
+
+	diags := &resp.Diagnostics
+	var data ConditionalFeaturesEvaluateModel
+
+	if diags.Append(req.Config.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	evaluationRequest := conditionalFeaturesRequest{
+		path:    data.Request.Path.ValueString(),
+		scheme:  data.Request.Scheme.ValueString(),
+		country: data.Request.Country.ValueString(),
+		query:   map[string]string{},
+		headers: map[string]string{},
+	}
+
+	if evaluationRequest.scheme == "" {
+		evaluationRequest.scheme = "https"
+	}
+
+	if !data.Request.ClientIp.IsNull() {
+		clientIp, err := netip.ParseAddr(data.Request.ClientIp.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("request").AtName("client_ip"), "Invalid IP address", err.Error())
+
+			return
+		}
+
+		evaluationRequest.clientIp = &clientIp
+	}
+
+	if diags.Append(data.Request.Query.ElementsAs(ctx, &evaluationRequest.query, false)...); diags.HasError() {
+		return
+	}
+
+	var headers map[string]string
+	if diags.Append(data.Request.Headers.ElementsAs(ctx, &headers, false)...); diags.HasError() {
+		return
+	}
+
+	for name, value := range headers {
+		evaluationRequest.headers[strings.ToLower(name)] = value
+	}
+
+	configuration := data.Configuration.ValueString()
+	if !validateConditionalFeaturesConfiguration(diags, path.Root("configuration"), configuration) {
+		return
+	}
+
+	evaluation, err := evaluateConditionalFeatures(configuration, evaluationRequest)
+	if err != nil {
+		diags.AddAttributeError(path.Root("configuration"), errMessage, err.Error())
+
+		return
+	}
+
+	data.MatchedRules = make([]int64, len(evaluation.matchedRules))
+	for i, rule := range evaluation.matchedRules {
+		data.MatchedRules[i] = int64(rule)
+	}
+
+	data.Actions = make([]ConditionalFeaturesEvaluateActionModel, len(evaluation.actions))
+	for i, action := range evaluation.actions {
+		data.Actions[i] = ConditionalFeaturesEvaluateActionModel{
+			Rule:   types.Int64Value(int64(action.rule)),
+			Name:   types.StringValue(action.name),
+			Config: types.StringPointerValue(action.config),
+		}
+	}
+
+	data.Variables = evaluation.variables
+
+	diags.Append(resp.State.Set(ctx, data)...)
+}
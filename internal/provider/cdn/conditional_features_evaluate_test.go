@@ -0,0 +1,236 @@
+package cdn_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const conditionalFeaturesVariablesConfiguration = `[
+  {"if": [{"type": "header", "name": "X-Debug", "value": "1"}], "then": [
+    {"name": "set_var", "config": {"key": "debug", "value": "on"}}
+  ]},
+  {"if": ["AND", {"type": "var", "key": "debug", "value": "on"}, {"type": "client-ip", "ip": ["10.0.0.0/8"]}],
+    "then": [{"name": "cache_disable"}]},
+  {"if": ["NOR", {"type": "country", "country": "CZ"}, {"type": "scheme", "scheme": "http"}],
+    "then": [{"name": "response_hdr", "config": {"response_hdr_name": "X-Foreign", "response_hdr_value": "1"}}]}
+]`
+
+func TestConditionalFeaturesEvaluateDataSource(t *testing.T) {
+	testCases := []struct {
+		name              string
+		configuration     string
+		request           cdn.ConditionalFeaturesEvaluateRequestModel
+		expectedRules     []int64
+		expectedActions   []string
+		expectedVariables map[string]string
+	}{
+		{
+			name:            "path prefix",
+			configuration:   conditionalFeaturesConfiguration,
+			request:         evaluateRequest("/atp/file.js", nil, "", "", ""),
+			expectedRules:   []int64{0},
+			expectedActions: []string{`0 response_hdr {"response_hdr_name":"Access-Control-Allow-Origin",`},
+		},
+		{
+			name:            "no config",
+			configuration:   conditionalFeaturesConfiguration,
+			request:         evaluateRequest("/dai/file.js", nil, "", "", ""),
+			expectedRules:   []int64{1},
+			expectedActions: []string{"1 cache_disable <null>"},
+		},
+		{
+			name: "path suffix with prefix argument",
+			configuration: `[{"if":[{"type":"path-suffix","prefix":".m3u8"}],"then":[{"name":"cache_disable"}]},` +
+				`{"if":[{"type":"path-suffix","suffix":".ts"}],"then":[{"name":"cache_disable"}]}]`,
+			request:         evaluateRequest("/live/index.m3u8", nil, "", "", ""),
+			expectedRules:   []int64{0},
+			expectedActions: []string{"0 cache_disable <null>"},
+		},
+		{
+			name:          "no match",
+			configuration: conditionalFeaturesConfiguration,
+			request:       evaluateRequest("/file.js", nil, "", "", ""),
+		},
+		{
+			name:          "variables",
+			configuration: conditionalFeaturesVariablesConfiguration,
+			request:       evaluateRequest("/file.js", map[string]string{"x-debug": "1"}, "CZ", "10.1.2.3", "https"),
+			expectedRules: []int64{0, 1},
+			expectedActions: []string{
+				`0 set_var {"key":"debug","value":"on"}`,
+				"1 cache_disable <null>",
+			},
+			expectedVariables: map[string]string{"debug": "on"},
+		},
+		{
+			name: "managed rule markers",
+			configuration: `[{"then":[{"name":"cache_disable"},` +
+				`{"name":"set_var","config":{"key":"terraform_rule","value":"10"}}]}]`,
+			request:         evaluateRequest("/file.js", nil, "", "", ""),
+			expectedRules:   []int64{0},
+			expectedActions: []string{"0 cache_disable <null>"},
+		},
+		{
+			name:            "negated operator",
+			configuration:   conditionalFeaturesVariablesConfiguration,
+			request:         evaluateRequest("/file.js", map[string]string{"X-Debug": "0"}, "DE", "10.1.2.3", "https"),
+			expectedRules:   []int64{2},
+			expectedActions: []string{`2 response_hdr {"response_hdr_name":"X-Foreign",`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := readConditionalFeaturesEvaluate(t, tc.configuration, tc.request)
+			if err != "" {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !slices.Equal(data.MatchedRules, tc.expectedRules) {
+				t.Errorf("expected matched rules %v, got %v", tc.expectedRules, data.MatchedRules)
+			}
+
+			if len(data.Actions) != len(tc.expectedActions) {
+				t.Fatalf("expected %d actions, got %d: %v", len(tc.expectedActions), len(data.Actions), data.Actions)
+			}
+
+			for i, action := range data.Actions {
+				config := "<null>"
+				if !action.Config.IsNull() {
+					config = action.Config.ValueString()
+				}
+
+				actual := strings.Join([]string{action.Rule.String(), action.Name.ValueString(), config}, " ")
+				if !strings.HasPrefix(actual, tc.expectedActions[i]) {
+					t.Errorf("expected action starting with %s, got %s", tc.expectedActions[i], actual)
+				}
+			}
+
+			if len(data.Variables) != len(tc.expectedVariables) {
+				t.Fatalf("expected variables %v, got %v", tc.expectedVariables, data.Variables)
+			}
+
+			for key, value := range tc.expectedVariables {
+				if data.Variables[key] != value {
+					t.Errorf("expected variable %s to be %s, got %s", key, value, data.Variables[key])
+				}
+			}
+		})
+	}
+}
+
+func TestConditionalFeaturesEvaluateDataSource_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configuration string
+		expectedError string
+	}{
+		{
+			name:          "unsupported condition type",
+			configuration: `[{"if":[{"type":"path-regex","regex":".*"}],"then":[{"name":"cache_disable"}]}]`,
+			expectedError: `Element "/0/if/0/type": unknown condition type "path-regex"`,
+		},
+		{
+			name:          "missing argument",
+			configuration: `[{"if":["OR",["AND",{"type":"path-prefix"}]],"then":[{"name":"cache_disable"}]}]`,
+			expectedError: `Element "/0/if/1/1": "path-prefix" condition must contain "prefix"`,
+		},
+		{
+			name:          "invalid configuration",
+			configuration: `[{"if":[{"type":"path-prefix","prefix":"/"}]}]`,
+			expectedError: `Element "/0": rule must contain "then"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readConditionalFeaturesEvaluate(t, tc.configuration, evaluateRequest("/file", nil, "", "", ""))
+			if !strings.HasPrefix(err, tc.expectedError) {
+				t.Errorf("expected error starting with %q, got %q", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func readConditionalFeaturesEvaluate(
+	t *testing.T,
+	configuration string,
+	request cdn.ConditionalFeaturesEvaluateRequestModel,
+) (cdn.ConditionalFeaturesEvaluateModel, string) {
+	t.Helper()
+
+	dataSource := cdn.NewConditionalFeaturesEvaluateDataSource()
+	schemaResp := datasource.SchemaResponse{}
+	dataSource.Schema(t.Context(), datasource.SchemaRequest{}, &schemaResp)
+
+	s := schemaResp.Schema
+	config := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+	model := cdn.ConditionalFeaturesEvaluateModel{Configuration: util.NewJsonValue(configuration), Request: request}
+
+	if diags := config.Set(t.Context(), model); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: config.Raw}}
+
+	dataSource.Read(t.Context(), req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		return cdn.ConditionalFeaturesEvaluateModel{}, resp.Diagnostics.Errors()[0].Detail()
+	}
+
+	var data cdn.ConditionalFeaturesEvaluateModel
+	if diags := resp.State.Get(t.Context(), &data); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	return data, ""
+}
+
+func evaluateRequest(
+	path string,
+	headers map[string]string,
+	country string,
+	clientIp string,
+	scheme string,
+) cdn.ConditionalFeaturesEvaluateRequestModel {
+	headersValue := types.MapNull(types.StringType)
+	if headers != nil {
+		headersValue = stringMap(flatten(headers)...)
+	}
+
+	optional := func(value string) types.String {
+		if value == "" {
+			return types.StringNull()
+		}
+
+		return types.StringValue(value)
+	}
+
+	return cdn.ConditionalFeaturesEvaluateRequestModel{
+		Path:     types.StringValue(path),
+		Query:    types.MapNull(types.StringType),
+		Headers:  headersValue,
+		Country:  optional(country),
+		ClientIp: optional(clientIp),
+		Scheme:   optional(scheme),
+	}
+}
+
+func flatten(m map[string]string) []string {
+	keysAndValues := make([]string, 0, len(m)*2)
+	for key, value := range m {
+		keysAndValues = append(keysAndValues, key, value)
+	}
+
+	return keysAndValues
+}
@@ -0,0 +1,340 @@
+package cdn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrConditionalFeaturesUnsupportedCondition = errors.New("unsupported condition type")
+	ErrConditionalFeaturesConditionArgument    = errors.New("invalid condition argument")
+)
+
+// conditionalFeaturesRequest is the synthetic request the conditional features rules are evaluated for.
+type conditionalFeaturesRequest struct {
+	path     string
+	scheme   string
+	country  string
+	clientIp *netip.Addr
+	query    map[string]string
+	headers  map[string]string // header names are lowercase
+}
+
+type conditionalFeaturesAction struct {
+	rule   int
+	name   string
+	config *string
+}
+
+type conditionalFeaturesEvaluation struct {
+	matchedRules []int
+	actions      []conditionalFeaturesAction
+	variables    map[string]string
+}
+
+// conditionTypeArgs maps the supported condition types to their arguments; the value tells if the argument is
+// required. Each argument is either a string or an array of strings.
+var conditionTypeArgs = map[string]map[string]bool{
+	"client-ip":   {"ip": true},
+	"country":     {"country": true},
+	"header":      {"name": true, "value": false},
+	"path-prefix": {"prefix": true},
+	"path-suffix": {"suffix": true},
+	"query-param": {"key": true, "value": false},
+	"scheme":      {"scheme": true},
+	"var":         {"key": true, "value": false},
+}
+
+// conditionArgAliases maps the alternative argument names accepted by the API to the documented ones.
+var conditionArgAliases = map[string]map[string]string{
+	"path-suffix": {"prefix": "suffix"},
+}
+
+// actionConfigProperties maps the supported actions to the JSON types of their (required) config properties.
+var actionConfigProperties = map[string]map[string]string{
+	"cache_disable":  {},
+	"replace_origin": {"origins": "array"},
+	"response_hdr":   {"response_hdr_name": "string", "response_hdr_value": "string"},
+	"set_var":        {"key": "string", "value": "string"},
+}
+
+func supportedConditionTypes() []string {
+	return slices.Sorted(maps.Keys(conditionTypeArgs))
+}
+
+func supportedActions() []string {
+	return slices.Sorted(maps.Keys(actionConfigProperties))
+}
+
+// evaluateConditionalFeatures evaluates the rules locally in the given order; variables set by the "set_var" action
+// of a matched rule are visible to the conditions of the following rules. The configuration must be valid
+// (see ConditionalFeaturesConfigurationValidator).
+func evaluateConditionalFeatures(
+	configuration string,
+	req conditionalFeaturesRequest,
+) (*conditionalFeaturesEvaluation, error) {
+	var rules []map[string]any
+	if err := json.Unmarshal([]byte(configuration), &rules); err != nil {
+		return nil, err
+	}
+
+	evaluation := &conditionalFeaturesEvaluation{
+		matchedRules: []int{},
+		actions:      []conditionalFeaturesAction{},
+		variables:    map[string]string{},
+	}
+
+	for i, rule := range rules {
+		pointer := jsonPointer("", i)
+		matches := true
+
+		if conditions, ok := rule["if"].([]any); ok {
+			var err error
+			if matches, err = evaluation.matchConditions(jsonPointer(pointer, "if"), conditions, req); err != nil {
+				return nil, err
+			}
+		}
+
+		if !matches {
+			continue
+		}
+
+		evaluation.matchedRules = append(evaluation.matchedRules, i)
+
+		actions, _ := rule["then"].([]any)
+		for _, item := range actions {
+			// The markers of rules managed by "cdn77_cdn_rule" resources only identify the rules.
+			if !isManagedRuleMarker(item) {
+				evaluation.applyAction(i, item)
+			}
+		}
+	}
+
+	return evaluation, nil
+}
+
+func (e *conditionalFeaturesEvaluation) applyAction(rule int, item any) {
+	action, _ := item.(map[string]any)
+	name, _ := action["name"].(string)
+	result := conditionalFeaturesAction{rule: rule, name: name}
+
+	if config, ok := action["config"]; ok {
+		raw, err := json.Marshal(config)
+		if err == nil {
+			rawString := string(raw)
+			result.config = &rawString
+		}
+	}
+
+	e.actions = append(e.actions, result)
+
+	if name != "set_var" {
+		return
+	}
+
+	config, _ := action["config"].(map[string]any)
+	key, keyOk := config["key"].(string)
+	value, valueOk := config["value"].(string)
+
+	if keyOk && valueOk {
+		e.variables[key] = value
+	}
+}
+
+func (e *conditionalFeaturesEvaluation) matchConditions(
+	pointer string,
+	items []any,
+	req conditionalFeaturesRequest,
+) (bool, error) {
+	operator := "AND"
+	matchCount := 0
+	conditionCount := 0
+
+	for i, item := range items {
+		var matches bool
+		var err error
+
+		switch item := item.(type) {
+		case string:
+			operator = item
+
+			continue
+		case []any:
+			matches, err = e.matchConditions(jsonPointer(pointer, i), item, req)
+		case map[string]any:
+			matches, err = e.matchCondition(jsonPointer(pointer, i), item, req)
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		conditionCount++
+
+		if matches {
+			matchCount++
+		}
+	}
+
+	switch operator {
+	case "OR":
+		return matchCount > 0, nil
+	case "NAND":
+		return matchCount != conditionCount, nil
+	case "NOR":
+		return matchCount == 0, nil
+	default:
+		return matchCount == conditionCount, nil
+	}
+}
+
+func (e *conditionalFeaturesEvaluation) matchCondition( //nolint:cyclop
+	pointer string,
+	condition map[string]any,
+	req conditionalFeaturesRequest,
+) (bool, error) {
+	conditionType, _ := condition["type"].(string)
+	args := conditionArgs{pointer: pointer, condition: condition, aliases: conditionArgAliases[conditionType]}
+
+	switch conditionType {
+	case "path-prefix":
+		return args.matchAny("prefix", func(prefix string) bool { return strings.HasPrefix(req.path, prefix) })
+	case "path-suffix":
+		return args.matchAny("suffix", func(suffix string) bool { return strings.HasSuffix(req.path, suffix) })
+	case "scheme":
+		return args.matchAny("scheme", func(scheme string) bool { return strings.EqualFold(req.scheme, scheme) })
+	case "country":
+		return args.matchAny("country", func(country string) bool { return strings.EqualFold(req.country, country) })
+	case "client-ip":
+		return args.matchClientIp(req.clientIp)
+	case "query-param":
+		return args.matchKeyValue("key", req.query, false)
+	case "header":
+		return args.matchKeyValue("name", req.headers, true)
+	case "var":
+		return args.matchKeyValue("key", e.variables, false)
+	default:
+		return false, fmt.Errorf(
+			"element %q: %w %q; supported types are %s",
+			jsonPointer(pointer, "type"),
+			ErrConditionalFeaturesUnsupportedCondition,
+			conditionType,
+			strings.Join(supportedConditionTypes(), ", "),
+		)
+	}
+}
+
+type conditionArgs struct {
+	pointer   string
+	condition map[string]any
+	aliases   map[string]string
+}
+
+// values returns the string values of the argument; the argument can be either a string or an array of strings.
+func (a conditionArgs) values(name string, required bool) ([]string, error) {
+	value, ok := a.condition[name]
+	for alias, aliased := range a.aliases {
+		if _, aliasOk := a.condition[alias]; !ok && aliasOk && aliased == name {
+			name, value, ok = alias, a.condition[alias], true
+		}
+	}
+
+	if !ok {
+		if required {
+			return nil, a.error(name, "argument is missing")
+		}
+
+		return nil, nil
+	}
+
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case []any:
+		values := make([]string, len(value))
+
+		for i, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, a.error(name, "argument must be a string or an array of strings")
+			}
+
+			values[i] = s
+		}
+
+		return values, nil
+	default:
+		return nil, a.error(name, "argument must be a string or an array of strings")
+	}
+}
+
+func (a conditionArgs) matchAny(name string, match func(string) bool) (bool, error) {
+	values, err := a.values(name, true)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(values, match), nil
+}
+
+func (a conditionArgs) matchClientIp(clientIp *netip.Addr) (bool, error) {
+	values, err := a.values("ip", true)
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range values {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				return false, a.error("ip", "argument must be an IP address or a CIDR")
+			}
+
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		if clientIp != nil && prefix.Contains(*clientIp) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// matchKeyValue matches if the entry exists and, if the "value" argument is set, has one of the given values.
+func (a conditionArgs) matchKeyValue(keyArg string, entries map[string]string, lowercaseKeys bool) (bool, error) {
+	keys, err := a.values(keyArg, true)
+	if err != nil {
+		return false, err
+	}
+
+	values, err := a.values("value", false)
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range keys {
+		if lowercaseKeys {
+			key = strings.ToLower(key)
+		}
+
+		entry, ok := entries[key]
+		if ok && (values == nil || slices.Contains(values, entry)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (a conditionArgs) error(name string, message string) error {
+	pointer := jsonPointer(a.pointer, name)
+
+	return fmt.Errorf("element %q: %w: %s", pointer, ErrConditionalFeaturesConditionArgument, message)
+}
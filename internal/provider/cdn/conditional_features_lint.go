@@ -0,0 +1,428 @@
+package cdn
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	lintSeverityOff     = "off"
+	lintSeverityWarning = "warning"
+	lintSeverityError   = "error"
+)
+
+type conditionalFeaturesLintIssue struct {
+	rule    int
+	message string
+}
+
+func lintSeverities() []string {
+	return []string{lintSeverityOff, lintSeverityWarning, lintSeverityError}
+}
+
+// lintConditionalFeatures looks for rules that are most likely a mistake: duplicate conditions, rules with the same
+// conditions as an earlier rule, conflicting actions, rules whose actions are all overridden by later rules that
+// always match and rules that can never match because of the variables set by earlier rules that always match.
+// The configuration must be valid (see ConditionalFeaturesConfigurationValidator).
+func lintConditionalFeatures(configuration string) []conditionalFeaturesLintIssue {
+	var rules []jsonRule
+	if err := json.Unmarshal([]byte(configuration), &rules); err != nil {
+		return nil
+	}
+
+	// The markers of rules managed by "cdn77_cdn_rule" resources would be reported as conflicting actions.
+	for i, rule := range rules {
+		rules[i] = withoutManagedRuleMarker(rule)
+	}
+
+	var issues []conditionalFeaturesLintIssue
+	conditionKeys := make([]string, len(rules))
+
+	for i, rule := range rules {
+		if rule.If != nil {
+			for _, message := range lintConditions(jsonPointer(jsonPointer("", i), "if"), *rule.If) {
+				issues = append(issues, conditionalFeaturesLintIssue{rule: i, message: message})
+			}
+		}
+
+		conditionKeys[i] = conditionGroupKey(rule.If)
+
+		if j := slices.Index(conditionKeys[:i], conditionKeys[i]); j != -1 {
+			issues = append(issues, conditionalFeaturesLintIssue{
+				rule:    i,
+				message: fmt.Sprintf("has the same conditions as rule #%d; merge their actions into one rule", j),
+			})
+		}
+
+		for _, message := range lintActions(rule.Then) {
+			issues = append(issues, conditionalFeaturesLintIssue{rule: i, message: message})
+		}
+	}
+
+	conditionVariables := map[string]struct{}{}
+	for _, rule := range rules {
+		if rule.If != nil {
+			collectConditionVariables(*rule.If, conditionVariables)
+		}
+	}
+
+	for i, rule := range rules {
+		if rule.Then == nil || len(*rule.Then) == 0 {
+			continue
+		}
+
+		if j, ok := overridingCatchAllRule(rules, i, conditionVariables); ok {
+			issues = append(issues, conditionalFeaturesLintIssue{
+				rule:    i,
+				message: fmt.Sprintf("has no effect; all its actions are overridden by catch-all rule #%d", j),
+			})
+		}
+	}
+
+	issues = append(issues, unreachableRules(rules)...)
+
+	slices.SortStableFunc(issues, func(a, b conditionalFeaturesLintIssue) int { return a.rule - b.rule })
+
+	return issues
+}
+
+func lintConditions(pointer string, items []json.RawMessage) []string {
+	var messages []string
+	seen := map[string]int{}
+
+	for i, item := range items {
+		var group []json.RawMessage
+		if json.Unmarshal(item, &group) == nil {
+			messages = append(messages, lintConditions(jsonPointer(pointer, i), group)...)
+		}
+
+		var condition map[string]any
+		if json.Unmarshal(item, &condition) != nil || condition == nil {
+			continue
+		}
+
+		key := canonicalJsonKey(condition)
+		if j, ok := seen[key]; ok {
+			messages = append(
+				messages,
+				fmt.Sprintf("condition %q duplicates condition %q", jsonPointer(pointer, i), jsonPointer(pointer, j)),
+			)
+
+			continue
+		}
+
+		seen[key] = i
+	}
+
+	return messages
+}
+
+func lintActions(actions *[]jsonAction) []string {
+	if actions == nil {
+		return nil
+	}
+
+	var messages []string
+	seen := map[string]int{}
+
+	for i, action := range *actions {
+		key := actionKey(action)
+		if j, ok := seen[key]; ok {
+			messages = append(messages, fmt.Sprintf("action #%d (%s) conflicts with action #%d", i, key, j))
+
+			continue
+		}
+
+		seen[key] = i
+	}
+
+	return messages
+}
+
+// overridingCatchAllRule returns the index of a later rule without conditions which overrides all actions of
+// the given rule. Setting a variable used in conditions is never considered overridden as it may affect the rules
+// in between.
+func overridingCatchAllRule(rules []jsonRule, i int, conditionVariables map[string]struct{}) (int, bool) {
+	if rules[i].Then == nil {
+		return 0, false
+	}
+
+	remaining := map[string]struct{}{}
+	for _, action := range *rules[i].Then {
+		key := actionKey(action)
+		if _, ok := conditionVariables[key]; ok {
+			return 0, false
+		}
+
+		remaining[key] = struct{}{}
+	}
+
+	for j := i + 1; j < len(rules); j++ {
+		if (rules[j].If != nil && len(*rules[j].If) > 0) || rules[j].Then == nil {
+			continue
+		}
+
+		for _, action := range *rules[j].Then {
+			delete(remaining, actionKey(action))
+		}
+
+		if len(remaining) == 0 {
+			return j, true
+		}
+	}
+
+	return 0, false
+}
+
+type catchAllVariable struct {
+	value string
+	rule  int
+}
+
+// unreachableRules returns the rules whose conditions can never match because an earlier catch-all rule (a rule
+// without conditions) sets the variables checked by the conditions to other values and no rule in between may
+// change them.
+func unreachableRules(rules []jsonRule) []conditionalFeaturesLintIssue {
+	var issues []conditionalFeaturesLintIssue
+	variables := map[string]catchAllVariable{}
+
+	for i, rule := range rules {
+		catchAll := rule.If == nil || len(*rule.If) == 0
+
+		if !catchAll {
+			used := map[string]struct{}{}
+			if matches, ok := staticConditionsMatch(*rule.If, variables, used); ok && !matches && len(used) > 0 {
+				name := slices.Sorted(maps.Keys(used))[0]
+				issues = append(issues, conditionalFeaturesLintIssue{
+					rule: i,
+					message: fmt.Sprintf(
+						"can never match; catch-all rule #%d sets variable %q to %q",
+						variables[name].rule,
+						name,
+						variables[name].value,
+					),
+				})
+
+				continue
+			}
+		}
+
+		if rule.Then == nil {
+			continue
+		}
+
+		for _, action := range *rule.Then {
+			if action.Name == nil || *action.Name != "set_var" || action.Config == nil {
+				continue
+			}
+
+			var config struct {
+				Key   *string `json:"key"`
+				Value *string `json:"value"`
+			}
+			if json.Unmarshal(*action.Config, &config) != nil || config.Key == nil {
+				continue
+			}
+
+			if catchAll && config.Value != nil {
+				variables[*config.Key] = catchAllVariable{value: *config.Value, rule: i}
+			} else {
+				delete(variables, *config.Key)
+			}
+		}
+	}
+
+	return issues
+}
+
+// staticConditionsMatch evaluates the conditions using only the variables set by catch-all rules; ok is false if
+// the result depends on the request. The names of the variables the result depends on are added to used.
+func staticConditionsMatch(
+	items []json.RawMessage,
+	variables map[string]catchAllVariable,
+	used map[string]struct{},
+) (bool, bool) {
+	operator := "AND"
+	matchCount, unknownCount, conditionCount := 0, 0, 0
+
+	for i, item := range items {
+		var s string
+		if i == 0 && json.Unmarshal(item, &s) == nil {
+			operator = s
+
+			continue
+		}
+
+		var matches, ok bool
+
+		var group []json.RawMessage
+		if json.Unmarshal(item, &group) == nil {
+			matches, ok = staticConditionsMatch(group, variables, used)
+		} else {
+			var condition map[string]any
+			if json.Unmarshal(item, &condition) == nil {
+				matches, ok = staticConditionMatch(condition, variables, used)
+			}
+		}
+
+		conditionCount++
+
+		switch {
+		case !ok:
+			unknownCount++
+		case matches:
+			matchCount++
+		}
+	}
+
+	// The result is known if it's the same however the unknown conditions evaluate.
+	results := map[bool]struct{}{}
+	for _, matched := range []int{matchCount, matchCount + unknownCount} {
+		switch operator {
+		case "OR":
+			results[matched > 0] = struct{}{}
+		case "NAND":
+			results[matched != conditionCount] = struct{}{}
+		case "NOR":
+			results[matched == 0] = struct{}{}
+		default:
+			results[matched == conditionCount] = struct{}{}
+		}
+	}
+
+	if len(results) != 1 {
+		return false, false
+	}
+
+	_, matches := results[true]
+
+	return matches, true
+}
+
+func staticConditionMatch(
+	condition map[string]any,
+	variables map[string]catchAllVariable,
+	used map[string]struct{},
+) (bool, bool) {
+	if condition["type"] != "var" {
+		return false, false
+	}
+
+	args := conditionArgs{condition: condition}
+
+	keys, err := args.values("key", true)
+	if err != nil {
+		return false, false
+	}
+
+	values, err := args.values("value", false)
+	if err != nil {
+		return false, false
+	}
+
+	for _, key := range keys {
+		variable, ok := variables[key]
+		if !ok {
+			return false, false
+		}
+
+		used[key] = struct{}{}
+
+		if values == nil || slices.Contains(values, variable.value) {
+			return true, true
+		}
+	}
+
+	return false, true
+}
+
+// actionKey identifies the setting changed by the action; actions with the same key override each other. Variables
+// and response headers are identified by their names as multiple of them can be set at once.
+func actionKey(action jsonAction) string {
+	name := ""
+	if action.Name != nil {
+		name = *action.Name
+	}
+
+	var config map[string]any
+	if action.Config != nil {
+		_ = json.Unmarshal(*action.Config, &config)
+	}
+
+	switch name {
+	case "set_var":
+		return setVarActionKey(config["key"])
+	case "response_hdr":
+		return fmt.Sprintf("%s %v", name, config["response_hdr_name"])
+	default:
+		return name
+	}
+}
+
+func collectConditionVariables(items []json.RawMessage, variables map[string]struct{}) {
+	for _, item := range items {
+		var group []json.RawMessage
+		if json.Unmarshal(item, &group) == nil {
+			collectConditionVariables(group, variables)
+
+			continue
+		}
+
+		var condition map[string]any
+		if json.Unmarshal(item, &condition) == nil && condition["type"] == "var" {
+			variables[setVarActionKey(condition["key"])] = struct{}{}
+		}
+	}
+}
+
+func setVarActionKey(variable any) string {
+	return fmt.Sprintf("set_var %v", variable)
+}
+
+// conditionGroupKey returns the same key for condition groups with the same operator and the same conditions in any
+// order.
+func conditionGroupKey(items *[]json.RawMessage) string {
+	if items == nil || len(*items) == 0 {
+		return ""
+	}
+
+	operator := "AND"
+	keys := make([]string, 0, len(*items))
+
+	for i, item := range *items {
+		var s string
+		if i == 0 && json.Unmarshal(item, &s) == nil {
+			operator = s
+
+			continue
+		}
+
+		var group []json.RawMessage
+		if json.Unmarshal(item, &group) == nil {
+			keys = append(keys, "("+conditionGroupKey(&group)+")")
+
+			continue
+		}
+
+		var condition any
+		if json.Unmarshal(item, &condition) == nil {
+			keys = append(keys, canonicalJsonKey(condition))
+		}
+	}
+
+	slices.Sort(keys)
+
+	return operator + " " + strings.Join(keys, " ")
+}
+
+func canonicalJsonKey(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(raw)
+}
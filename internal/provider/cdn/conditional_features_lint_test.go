@@ -0,0 +1,153 @@
+package cdn_test
+
+import (
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const conditionalFeaturesLintConfiguration = `[
+  {"if": [{"type": "path-prefix", "prefix": "/a"}, {"type": "path-prefix", "prefix": "/a"}],
+    "then": [{"name": "cache_disable"}]},
+  {"if": ["AND", {"type": "var", "key": "x", "value": "1"}, {"type": "path-prefix", "prefix": "/a"}],
+    "then": [{"name": "set_var", "config": {"key": "a", "value": "1"}}]},
+  {"if": [{"type": "path-prefix", "prefix": "/a"}, {"type": "var", "key": "x", "value": "1"}],
+    "then": [
+      {"name": "set_var", "config": {"key": "a", "value": "2"}},
+      {"name": "set_var", "config": {"key": "b", "value": "2"}},
+      {"name": "set_var", "config": {"key": "a", "value": "3"}}
+    ]},
+  {"then": [{"name": "cache_disable"}, {"name": "set_var", "config": {"key": "a", "value": "4"}}]}
+]`
+
+func TestResource_ModifyPlanLint(t *testing.T) {
+	testCases := []struct {
+		name             string
+		configuration    string
+		severity         types.String
+		expectedSeverity diag.Severity
+		expectedIssues   []string
+	}{
+		{
+			name:          "no issues",
+			configuration: conditionalFeaturesConfiguration,
+			severity:      types.StringNull(),
+		},
+		{
+			name:             "warnings",
+			configuration:    conditionalFeaturesLintConfiguration,
+			severity:         types.StringNull(),
+			expectedSeverity: diag.SeverityWarning,
+			expectedIssues: []string{
+				`Conditional features rule #0 condition "/0/if/1" duplicates condition "/0/if/0"`,
+				"Conditional features rule #0 has no effect; all its actions are overridden by catch-all rule #3",
+				"Conditional features rule #1 has no effect; all its actions are overridden by catch-all rule #3",
+				"Conditional features rule #2 has the same conditions as rule #1; merge their actions into one rule",
+				"Conditional features rule #2 action #2 (set_var a) conflicts with action #0",
+			},
+		},
+		{
+			name:             "errors",
+			configuration:    `[{"then":[{"name":"cache_disable"}]},{"then":[{"name":"cache_disable"}]}]`,
+			severity:         types.StringValue("error"),
+			expectedSeverity: diag.SeverityError,
+			expectedIssues: []string{
+				"Conditional features rule #0 has no effect; all its actions are overridden by catch-all rule #1",
+				"Conditional features rule #1 has the same conditions as rule #0; merge their actions into one rule",
+			},
+		},
+		{
+			name: "variable used in conditions",
+			configuration: `[{"then":[{"name":"set_var","config":{"key":"a","value":"1"}}]},` +
+				`{"if":[{"type":"var","key":"a","value":"1"}],"then":[{"name":"cache_disable"}]},` +
+				`{"then":[{"name":"set_var","config":{"key":"a","value":"2"}}]}]`,
+			severity:         types.StringValue("warning"),
+			expectedSeverity: diag.SeverityWarning,
+			expectedIssues: []string{
+				"Conditional features rule #2 has the same conditions as rule #0; merge their actions into one rule",
+			},
+		},
+		{
+			name: "unreachable rules",
+			configuration: `[{"then":[{"name":"set_var","config":{"key":"env","value":"prod"}},` +
+				`{"name":"set_var","config":{"key":"tier","value":"1"}}]},` +
+				`{"if":[{"type":"var","key":"env","value":"staging"}],"then":[{"name":"cache_disable"}]},` +
+				`{"if":["AND",{"type":"var","key":"tier","value":["2","3"]},{"type":"path-prefix","prefix":"/a"}],` +
+				`"then":[{"name":"response_hdr","config":{"response_hdr_name":"a","response_hdr_value":"1"}}]},` +
+				`{"if":["OR",{"type":"var","key":"env","value":"staging"},{"type":"path-prefix","prefix":"/b"}],` +
+				`"then":[{"name":"response_hdr","config":{"response_hdr_name":"b","response_hdr_value":"1"}}]},` +
+				`{"if":[{"type":"path-prefix","prefix":"/c"}],` +
+				`"then":[{"name":"set_var","config":{"key":"env","value":"staging"}}]},` +
+				`{"if":[{"type":"var","key":"env","value":["dev","staging"]}],` +
+				`"then":[{"name":"response_hdr","config":{"response_hdr_name":"c","response_hdr_value":"1"}}]},` +
+				`{"if":["NOR",{"type":"var","key":"tier"}],` +
+				`"then":[{"name":"response_hdr","config":{"response_hdr_name":"d","response_hdr_value":"1"}}]}]`,
+			severity:         types.StringNull(),
+			expectedSeverity: diag.SeverityWarning,
+			expectedIssues: []string{
+				`Conditional features rule #1 can never match; catch-all rule #0 sets variable "env" to "prod"`,
+				`Conditional features rule #2 can never match; catch-all rule #0 sets variable "tier" to "1"`,
+				`Conditional features rule #6 can never match; catch-all rule #0 sets variable "tier" to "1"`,
+			},
+		},
+		{
+			name: "managed rule markers",
+			configuration: `[{"then":[{"name":"cache_disable"},` +
+				`{"name":"set_var","config":{"key":"terraform_rule","value":"10"}}]},` +
+				`{"if":[{"type":"var","key":"terraform_rule","value":"20"}],"then":[{"name":"cache_disable"},` +
+				`{"name":"set_var","config":{"key":"terraform_rule","value":"20"}}]}]`,
+			severity: types.StringNull(),
+		},
+		{
+			name:          "unknown severity",
+			configuration: conditionalFeaturesLintConfiguration,
+			severity:      types.StringUnknown(),
+		},
+		{
+			name:          "off",
+			configuration: conditionalFeaturesLintConfiguration,
+			severity:      types.StringValue("off"),
+		},
+		{
+			name:          "invalid configuration",
+			configuration: `[{"if":[]}]`,
+			severity:      types.StringNull(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := cdn.CreateResourceSchema()
+			plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+			configurationPath := path.Root("conditional_features").AtName("configuration")
+
+			diags := plan.SetAttribute(t.Context(), configurationPath, util.NewJsonValue(tc.configuration))
+			diags.Append(plan.SetAttribute(t.Context(), path.Root("conditional_features_lint"), tc.severity)...)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			(&cdn.Resource{}).ModifyPlan(t.Context(), resource.ModifyPlanRequest{Plan: plan}, &resp)
+
+			if len(resp.Diagnostics) != len(tc.expectedIssues) {
+				t.Fatalf("expected %d issues, got %v", len(tc.expectedIssues), resp.Diagnostics)
+			}
+
+			for i, expectedIssue := range tc.expectedIssues {
+				d := resp.Diagnostics[i]
+				if d.Summary() != expectedIssue || d.Severity() != tc.expectedSeverity {
+					t.Errorf("expected %s %q, got %s %q", tc.expectedSeverity, expectedIssue, d.Severity(), d.Summary())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package cdn_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const conditionalFeaturesConfiguration = `[` +
+	`{"if":["OR",{"prefix":"/atp","type":"path-prefix"},{"key":"set-cors","type":"var","value":"true"}],` +
+	`"then":[{"config":{"response_hdr_name":"Access-Control-Allow-Origin","response_hdr_value":"*"},` +
+	`"name":"response_hdr"}]},` +
+	`{"if":[{"prefix":"/dai","type":"path-prefix"}],"then":[{"name":"cache_disable"}]}` +
+	`]`
+
+func TestResource_UpgradeStateV0(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configuration string
+		expectedRules []cdn.ModelConditionalFeaturesRule
+	}{
+		{
+			name:          "rules",
+			configuration: conditionalFeaturesConfiguration,
+			expectedRules: []cdn.ModelConditionalFeaturesRule{
+				{
+					If: &cdn.ModelConditionalFeaturesIf{
+						Operator: types.StringValue("OR"),
+						Conditions: []cdn.ModelConditionalFeaturesCondition{
+							{
+								Type:     types.StringValue("path-prefix"),
+								Args:     stringMap("prefix", "/atp"),
+								ListArgs: nullListArgs(),
+							},
+							{
+								Type:     types.StringValue("var"),
+								Args:     stringMap("key", "set-cors", "value", "true"),
+								ListArgs: nullListArgs(),
+							},
+						},
+					},
+					Then: []cdn.ModelConditionalFeaturesAction{
+						{
+							Name: types.StringValue("response_hdr"),
+							Config: util.NewJsonValue(
+								`{"response_hdr_name":"Access-Control-Allow-Origin","response_hdr_value":"*"}`,
+							),
+						},
+					},
+				},
+				{
+					If: &cdn.ModelConditionalFeaturesIf{
+						Operator: types.StringNull(),
+						Conditions: []cdn.ModelConditionalFeaturesCondition{
+							{
+								Type:     types.StringValue("path-prefix"),
+								Args:     stringMap("prefix", "/dai"),
+								ListArgs: nullListArgs(),
+							},
+						},
+					},
+					Then: []cdn.ModelConditionalFeaturesAction{
+						{Name: types.StringValue("cache_disable"), Config: util.NewJsonNull()},
+					},
+				},
+			},
+		},
+		{
+			name:          "nested condition groups",
+			configuration: `[{"if":[["OR",{"type":"var","key":"a","value":"b"}]],"then":[{"name":"cache_disable"}]}]`,
+		},
+		{
+			name:          "boolean condition argument",
+			configuration: `[{"if":[{"type":"var","key":"a","value":true}],"then":[{"name":"cache_disable"}]}]`,
+		},
+		{
+			name:          "array of numbers condition argument",
+			configuration: `[{"if":[{"type":"var","key":"a","value":[1,2]}],"then":[{"name":"cache_disable"}]}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rawState, err := json.Marshal(map[string]any{
+				"id":                   1234567890,
+				"label":                "some label",
+				"origin_id":            "7c9b1a4d-4e3f-4b7a-9f3a-2f1c3b5d6e7f",
+				"conditional_features": map[string]any{"configuration": tc.configuration},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			upgrader := (&cdn.Resource{}).UpgradeState(t.Context())[0]
+			req := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: rawState}}
+			resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: cdn.CreateResourceSchema()}}
+
+			upgrader.StateUpgrader(t.Context(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var data cdn.Model
+			if diags := resp.State.Get(t.Context(), &data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if data.Label.ValueString() != "some label" {
+				t.Errorf("expected label to be kept, got %s", data.Label)
+			}
+
+			// The compact configuration of old states is rewritten, so it matches the value read from the API.
+			configuration := data.ConditionalFeatures.Configuration.ValueString()
+			if !util.JsonEqual(configuration, tc.configuration) || !strings.Contains(configuration, "\n  ") {
+				t.Errorf("expected pretty printed %s, got %s", tc.configuration, configuration)
+			}
+
+			assertRules(t, data.ConditionalFeatures.Rule, tc.expectedRules)
+		})
+	}
+}
+
+func TestConfigurationAndRulePlanModifier(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	configurationPath := path.Root("conditional_features").AtName("configuration")
+	rulePath := path.Root("conditional_features").AtName("rule")
+
+	configWith := func(attrPath path.Path, value any) tfsdk.Config {
+		state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+		if diags := state.SetAttribute(t.Context(), attrPath, value); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		return tfsdk.Config{Schema: s, Raw: state.Raw}
+	}
+
+	// configuration -> rule
+	ruleReq := planmodifier.ListRequest{
+		Path:        rulePath,
+		Config:      configWith(configurationPath, types.StringValue(conditionalFeaturesConfiguration)),
+		ConfigValue: types.ListNull(types.ObjectType{}),
+	}
+	ruleResp := planmodifier.ListResponse{}
+
+	cdn.ConfigurationAndRulePlanModifier{}.PlanModifyList(t.Context(), ruleReq, &ruleResp)
+
+	if ruleResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", ruleResp.Diagnostics)
+	}
+
+	if ruleResp.PlanValue.IsNull() || len(ruleResp.PlanValue.Elements()) != 2 {
+		t.Fatalf("expected 2 rules, got %s", ruleResp.PlanValue)
+	}
+
+	// rule -> configuration
+	configurationReq := planmodifier.StringRequest{
+		Path:        configurationPath,
+		Config:      configWith(rulePath, ruleResp.PlanValue),
+		ConfigValue: types.StringNull(),
+	}
+	configurationResp := planmodifier.StringResponse{}
+
+	cdn.ConfigurationAndRulePlanModifier{}.PlanModifyString(t.Context(), configurationReq, &configurationResp)
+
+	if configurationResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", configurationResp.Diagnostics)
+	}
+
+	configuration := configurationResp.PlanValue.ValueString()
+	if !util.JsonEqual(configuration, conditionalFeaturesConfiguration) || !strings.Contains(configuration, "\n  ") {
+		t.Errorf("expected pretty printed %s, got %s", conditionalFeaturesConfiguration, configuration)
+	}
+
+	// rule -> configuration, the semantically equal state value is kept
+	configurationReq.StateValue = types.StringValue(conditionalFeaturesConfiguration)
+	configurationResp = planmodifier.StringResponse{}
+
+	cdn.ConfigurationAndRulePlanModifier{}.PlanModifyString(t.Context(), configurationReq, &configurationResp)
+
+	if !configurationResp.PlanValue.Equal(configurationReq.StateValue) {
+		t.Errorf("expected state value %s, got %s", configurationReq.StateValue, configurationResp.PlanValue)
+	}
+
+	// neither is set
+	nullReq := planmodifier.StringRequest{
+		Path:        configurationPath,
+		Config:      configWith(path.Root("label"), types.StringValue("some label")),
+		ConfigValue: types.StringNull(),
+		PlanValue:   types.StringValue(conditionalFeaturesConfiguration),
+	}
+	nullResp := planmodifier.StringResponse{PlanValue: nullReq.PlanValue}
+
+	cdn.ConfigurationAndRulePlanModifier{}.PlanModifyString(t.Context(), nullReq, &nullResp)
+
+	if !nullResp.PlanValue.IsNull() {
+		t.Errorf("expected null configuration, got %s", nullResp.PlanValue)
+	}
+
+	// an argument set in both "args" and "list_args"
+	duplicateArgReq := planmodifier.StringRequest{
+		Path: configurationPath,
+		Config: configWith(rulePath, []cdn.ModelConditionalFeaturesRule{{
+			If: &cdn.ModelConditionalFeaturesIf{
+				Operator: types.StringNull(),
+				Conditions: []cdn.ModelConditionalFeaturesCondition{{
+					Type:     types.StringValue("var"),
+					Args:     stringMap("key", "tier", "value", "gold"),
+					ListArgs: listArgs("value", "gold", "silver"),
+				}},
+			},
+			Then: []cdn.ModelConditionalFeaturesAction{
+				{Name: types.StringValue("cache_disable"), Config: util.NewJsonNull()},
+			},
+		}}),
+		ConfigValue: types.StringNull(),
+	}
+	duplicateArgResp := planmodifier.StringResponse{}
+
+	cdn.ConfigurationAndRulePlanModifier{}.PlanModifyString(t.Context(), duplicateArgReq, &duplicateArgResp)
+
+	if diags := duplicateArgResp.Diagnostics; !diags.HasError() || !strings.Contains(diags[0].Detail(), "list_args") {
+		t.Errorf("expected an error for the duplicate argument, got %v", diags)
+	}
+}
+
+func TestConfigurationAndRulePlanModifier_RoundTrip(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	configurationPath := path.Root("conditional_features").AtName("configuration")
+	rulePath := path.Root("conditional_features").AtName("rule")
+
+	configWith := func(attrPath path.Path, value any) tfsdk.Config {
+		state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+		if diags := state.SetAttribute(t.Context(), attrPath, value); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		return tfsdk.Config{Schema: s, Raw: state.Raw}
+	}
+
+	testCases := map[string]struct {
+		condition        string
+		expectedArgs     types.Map
+		expectedListArgs types.Map
+	}{
+		"strings": {
+			condition:        `{"prefix":"/atp","type":"path-prefix"}`,
+			expectedArgs:     stringMap("prefix", "/atp"),
+			expectedListArgs: nullListArgs(),
+		},
+		"strings which are valid JSON": {
+			condition:        `{"key":"set-cors","type":"var","value":"true"}`,
+			expectedArgs:     stringMap("key", "set-cors", "value", "true"),
+			expectedListArgs: nullListArgs(),
+		},
+		"arrays of strings": {
+			condition:        `{"country":["CZ","SK"],"type":"country"}`,
+			expectedArgs:     types.MapNull(types.StringType),
+			expectedListArgs: listArgs("country", "CZ", "SK"),
+		},
+		"strings and arrays of strings": {
+			condition:        `{"key":"tier","type":"var","value":["gold","silver"]}`,
+			expectedArgs:     stringMap("key", "tier"),
+			expectedListArgs: listArgs("value", "gold", "silver"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			configuration := `[{"if":[` + tc.condition + `],"then":[{"name":"cache_disable"}]}]`
+
+			ruleReq := planmodifier.ListRequest{
+				Path:        rulePath,
+				Config:      configWith(configurationPath, types.StringValue(configuration)),
+				ConfigValue: types.ListNull(types.ObjectType{}),
+			}
+			ruleResp := planmodifier.ListResponse{}
+
+			cdn.ConfigurationAndRulePlanModifier{}.PlanModifyList(t.Context(), ruleReq, &ruleResp)
+
+			if ruleResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", ruleResp.Diagnostics)
+			}
+
+			var rules []cdn.ModelConditionalFeaturesRule
+			if diags := ruleResp.PlanValue.ElementsAs(t.Context(), &rules, false); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			condition := rules[0].If.Conditions[0]
+
+			if !condition.Args.Equal(tc.expectedArgs) {
+				t.Errorf("expected args %s, got %s", tc.expectedArgs, condition.Args)
+			}
+
+			if !condition.ListArgs.Equal(tc.expectedListArgs) {
+				t.Errorf("expected list args %s, got %s", tc.expectedListArgs, condition.ListArgs)
+			}
+
+			configurationReq := planmodifier.StringRequest{
+				Path:        configurationPath,
+				Config:      configWith(rulePath, ruleResp.PlanValue),
+				ConfigValue: types.StringNull(),
+			}
+			configurationResp := planmodifier.StringResponse{}
+
+			cdn.ConfigurationAndRulePlanModifier{}.PlanModifyString(t.Context(), configurationReq, &configurationResp)
+
+			if configurationResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", configurationResp.Diagnostics)
+			}
+
+			if actual := configurationResp.PlanValue.ValueString(); !util.JsonEqual(actual, configuration) {
+				t.Errorf("expected %s, got %s", configuration, actual)
+			}
+		})
+	}
+}
+
+func assertRules(t *testing.T, actual types.List, expected []cdn.ModelConditionalFeaturesRule) {
+	t.Helper()
+
+	if expected == nil {
+		if !actual.IsNull() {
+			t.Errorf("expected null rules, got %s", actual)
+		}
+
+		return
+	}
+
+	expectedList, diags := types.ListValueFrom(t.Context(), actual.ElementType(t.Context()), expected)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if !actual.Equal(expectedList) {
+		t.Errorf("expected rules %s, got %s", expectedList, actual)
+	}
+}
+
+func stringMap(keysAndValues ...string) types.Map {
+	values := make(map[string]attr.Value, len(keysAndValues)/2)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		values[keysAndValues[i]] = types.StringValue(keysAndValues[i+1])
+	}
+
+	return types.MapValueMust(types.StringType, values)
+}
+
+func nullListArgs() types.Map {
+	return types.MapNull(types.ListType{ElemType: types.StringType})
+}
+
+func listArgs(name string, values ...string) types.Map {
+	elements := make([]attr.Value, len(values))
+
+	for i, value := range values {
+		elements[i] = types.StringValue(value)
+	}
+
+	return types.MapValueMust(
+		types.ListType{ElemType: types.StringType},
+		map[string]attr.Value{name: types.ListValueMust(types.StringType, elements)},
+	)
+}
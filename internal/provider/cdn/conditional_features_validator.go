@@ -0,0 +1,420 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// ConditionalFeaturesConfigurationValidator checks the structure of the conditional features rules, the arguments of
+// the conditions and the configs of the actions, so mistakes are reported during validation instead of failing the API
+// call (and rolling back the just created CDN).
+type ConditionalFeaturesConfigurationValidator struct{}
+
+func (ConditionalFeaturesConfigurationValidator) Description(context.Context) string {
+	return "value must be a valid conditional features configuration"
+}
+
+func (v ConditionalFeaturesConfigurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (ConditionalFeaturesConfigurationValidator) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	validateConditionalFeaturesConfiguration(&resp.Diagnostics, req.Path, req.ConfigValue.ValueString())
+}
+
+func validateConditionalFeaturesConfiguration(diags *diag.Diagnostics, attrPath path.Path, raw string) bool {
+	var configuration any
+	if err := json.Unmarshal([]byte(raw), &configuration); err != nil {
+		diags.AddAttributeError(attrPath, "Invalid JSON", fmt.Sprintf("Configuration must contain valid JSON: %v", err))
+
+		return false
+	}
+
+	v := &conditionalFeaturesValidation{}
+	v.validateRules(configuration)
+
+	for _, e := range v.errors {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid conditional features configuration",
+			fmt.Sprintf("Element %q: %s", e.pointer, e.message),
+		)
+	}
+
+	return len(v.errors) == 0
+}
+
+type conditionalFeaturesError struct {
+	pointer string
+	message string
+}
+
+type conditionalFeaturesValidation struct {
+	errors []conditionalFeaturesError
+}
+
+func (v *conditionalFeaturesValidation) addError(pointer string, format string, args ...any) {
+	v.errors = append(v.errors, conditionalFeaturesError{pointer: pointer, message: fmt.Sprintf(format, args...)})
+}
+
+func (v *conditionalFeaturesValidation) validateRules(value any) {
+	rules, ok := value.([]any)
+	if !ok {
+		v.addError("", "configuration must be an array of rules, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	for i, rule := range rules {
+		v.validateRule(jsonPointer("", i), rule)
+	}
+}
+
+func (v *conditionalFeaturesValidation) validateRule(pointer string, value any) {
+	rule, ok := value.(map[string]any)
+	if !ok {
+		v.addError(pointer, "rule must be an object, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(rule)) {
+		if key != "if" && key != "then" {
+			v.addError(jsonPointer(pointer, key), `unknown rule property; only "if" and "then" are allowed`)
+		}
+	}
+
+	if conditions, ok := rule["if"]; ok {
+		v.validateConditions(jsonPointer(pointer, "if"), conditions)
+	}
+
+	actions, ok := rule["then"]
+	if !ok {
+		v.addError(pointer, `rule must contain "then"`)
+
+		return
+	}
+
+	v.validateActions(jsonPointer(pointer, "then"), actions)
+}
+
+func (v *conditionalFeaturesValidation) validateConditions(pointer string, value any) {
+	items, ok := value.([]any)
+	if !ok {
+		v.addError(pointer, "conditions must be an array, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	conditionCount := 0
+
+	for i, item := range items {
+		itemPointer := jsonPointer(pointer, i)
+
+		switch item := item.(type) {
+		case string:
+			switch {
+			case i != 0:
+				v.addError(itemPointer, "operator is allowed only as the first element of the conditions")
+			case !slices.Contains(conditionOperators(), item):
+				v.addError(
+					itemPointer,
+					"unknown operator %q; must be one of %s",
+					item,
+					strings.Join(conditionOperators(), ", "),
+				)
+			}
+		case []any:
+			conditionCount++
+
+			v.validateConditions(itemPointer, item)
+		case map[string]any:
+			conditionCount++
+
+			v.validateCondition(itemPointer, item)
+		default:
+			v.addError(itemPointer, "condition must be an object or an array, got %s", jsonTypeName(item))
+		}
+	}
+
+	if conditionCount == 0 {
+		v.addError(pointer, "conditions must contain at least one condition")
+	}
+}
+
+func (v *conditionalFeaturesValidation) validateCondition(pointer string, condition map[string]any) {
+	value, ok := condition["type"]
+	if !ok {
+		v.addError(pointer, `condition must contain "type"`)
+
+		return
+	}
+
+	conditionType, ok := value.(string)
+	if !ok || conditionType == "" {
+		v.addError(jsonPointer(pointer, "type"), "condition type must be a non-empty string")
+
+		return
+	}
+
+	args, ok := conditionTypeArgs[conditionType]
+	if !ok {
+		v.addError(
+			jsonPointer(pointer, "type"),
+			"unknown condition type %q; must be one of %s",
+			conditionType,
+			strings.Join(supportedConditionTypes(), ", "),
+		)
+
+		return
+	}
+
+	aliases := conditionArgAliases[conditionType]
+	present := map[string]bool{}
+
+	for _, name := range slices.Sorted(maps.Keys(condition)) {
+		if name == "type" {
+			continue
+		}
+
+		argName := name
+		if aliased, ok := aliases[name]; ok {
+			argName = aliased
+
+			if _, ok := condition[aliased]; ok {
+				v.addError(pointer, "%q condition must contain only one of %q and %q", conditionType, name, aliased)
+
+				continue
+			}
+		}
+
+		present[argName] = true
+
+		if _, ok := args[argName]; !ok {
+			v.addError(
+				jsonPointer(pointer, name),
+				"unknown argument of %q condition; allowed arguments are %s",
+				conditionType,
+				strings.Join(slices.Sorted(maps.Keys(args)), ", "),
+			)
+
+			continue
+		}
+
+		v.validateConditionArg(jsonPointer(pointer, name), conditionType, condition[name])
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		if args[name] && !present[name] {
+			v.addError(pointer, "%q condition must contain %q", conditionType, name)
+		}
+	}
+}
+
+func (v *conditionalFeaturesValidation) validateConditionArg(pointer string, conditionType string, value any) {
+	var values []any
+
+	switch value := value.(type) {
+	case string:
+		values = []any{value}
+	case []any:
+		values = value
+	}
+
+	if len(values) == 0 {
+		v.addError(pointer, "argument must be a string or a non-empty array of strings, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	for i, item := range values {
+		itemPointer := pointer
+		if _, ok := value.([]any); ok {
+			itemPointer = jsonPointer(pointer, i)
+		}
+
+		s, ok := item.(string)
+		if !ok {
+			v.addError(itemPointer, "argument value must be a string, got %s", jsonTypeName(item))
+
+			continue
+		}
+
+		if conditionType == "client-ip" && !isIpOrCidr(s) {
+			v.addError(itemPointer, "argument value must be an IP address or a CIDR, got %q", s)
+		}
+	}
+}
+
+func (v *conditionalFeaturesValidation) validateActions(pointer string, value any) {
+	actions, ok := value.([]any)
+	if !ok {
+		v.addError(pointer, "actions must be an array, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	if len(actions) == 0 {
+		v.addError(pointer, "actions must contain at least one action")
+	}
+
+	for i, action := range actions {
+		v.validateAction(jsonPointer(pointer, i), action)
+	}
+}
+
+func (v *conditionalFeaturesValidation) validateAction(pointer string, value any) {
+	action, ok := value.(map[string]any)
+	if !ok {
+		v.addError(pointer, "action must be an object, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(action)) {
+		if key != "name" && key != "config" {
+			v.addError(jsonPointer(pointer, key), `unknown action property; only "name" and "config" are allowed`)
+		}
+	}
+
+	name, ok := action["name"]
+	if !ok {
+		v.addError(pointer, `action must contain "name"`)
+
+		return
+	}
+
+	actionName, ok := name.(string)
+	if !ok || actionName == "" {
+		v.addError(jsonPointer(pointer, "name"), "action name must be a non-empty string")
+
+		return
+	}
+
+	properties, ok := actionConfigProperties[actionName]
+	if !ok {
+		v.addError(
+			jsonPointer(pointer, "name"),
+			"unknown action %q; must be one of %s",
+			actionName,
+			strings.Join(supportedActions(), ", "),
+		)
+
+		return
+	}
+
+	config, ok := action["config"]
+	if !ok {
+		if len(properties) != 0 {
+			v.addError(pointer, `%q action must contain "config"`, actionName)
+		}
+
+		return
+	}
+
+	v.validateActionConfig(jsonPointer(pointer, "config"), actionName, properties, config)
+}
+
+func (v *conditionalFeaturesValidation) validateActionConfig(
+	pointer string,
+	actionName string,
+	properties map[string]string,
+	value any,
+) {
+	var config map[string]any
+
+	switch value := value.(type) {
+	case map[string]any:
+		config = value
+	case []any:
+		// The API returns an empty config as an empty array.
+		if len(value) != 0 {
+			v.addError(pointer, "action config must be an object, got array")
+
+			return
+		}
+	default:
+		v.addError(pointer, "action config must be an object, got %s", jsonTypeName(value))
+
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		expectedType, ok := properties[key]
+		switch {
+		case !ok && len(properties) == 0:
+			v.addError(jsonPointer(pointer, key), "%q action has no config properties", actionName)
+		case !ok:
+			v.addError(
+				jsonPointer(pointer, key),
+				"unknown config property of %q action; allowed properties are %s",
+				actionName,
+				strings.Join(slices.Sorted(maps.Keys(properties)), ", "),
+			)
+		case jsonTypeName(config[key]) != expectedType:
+			v.addError(
+				jsonPointer(pointer, key),
+				"config property must be of type %s, got %s",
+				expectedType,
+				jsonTypeName(config[key]),
+			)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(properties)) {
+		if _, ok := config[key]; !ok {
+			v.addError(pointer, "config of %q action must contain %q", actionName, key)
+		}
+	}
+}
+
+func isIpOrCidr(value string) bool {
+	if _, err := netip.ParsePrefix(value); err == nil {
+		return true
+	}
+
+	_, err := netip.ParseAddr(value)
+
+	return err == nil
+}
+
+// jsonPointer appends the reference token to the pointer as described in RFC 6901.
+func jsonPointer(pointer string, token any) string {
+	return pointer + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(fmt.Sprint(token))
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
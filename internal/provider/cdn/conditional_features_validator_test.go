@@ -0,0 +1,135 @@
+package cdn_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestConditionalFeaturesConfigurationValidator(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configuration  string
+		expectedErrors []string
+	}{
+		{
+			name:          "valid",
+			configuration: conditionalFeaturesConfiguration,
+		},
+		{
+			name: "nested condition groups and empty config",
+			configuration: `[{"if":["NOR",["OR",{"type":"var","key":"a","value":"b"}],` +
+				`{"type":"path-prefix","prefix":["/a","/b"]},` +
+				`{"type":"path-suffix","prefix":".m3u8"}],` +
+				`"then":[{"name":"cache_disable","config":[]}]}]`,
+		},
+		{
+			name:           "invalid JSON",
+			configuration:  `[{"then":`,
+			expectedErrors: []string{"Configuration must contain valid JSON"},
+		},
+		{
+			name:           "not an array",
+			configuration:  `{"then":[]}`,
+			expectedErrors: []string{`Element "": configuration must be an array of rules, got object`},
+		},
+		{
+			name: "invalid rule",
+			configuration: `[{"then":[{"name":"cache_disable"}]},` +
+				`{"if":[{"type":"var","key":"a"}],"when":[]},"rule"]`,
+			expectedErrors: []string{
+				`Element "/1/when": unknown rule property`,
+				`Element "/1": rule must contain "then"`,
+				`Element "/2": rule must be an object, got string`,
+			},
+		},
+		{
+			name: "invalid conditions",
+			configuration: `[{"if":["XOR",{"type":""},"AND",["OR"],{"prefix":"/a"},1],` +
+				`"then":[{"name":"cache_disable"}]}]`,
+			expectedErrors: []string{
+				`Element "/0/if/0": unknown operator "XOR"`,
+				`Element "/0/if/1/type": condition type must be a non-empty string`,
+				`Element "/0/if/2": operator is allowed only as the first element`,
+				`Element "/0/if/3": conditions must contain at least one condition`,
+				`Element "/0/if/4": condition must contain "type"`,
+				`Element "/0/if/5": condition must be an object or an array, got number`,
+			},
+		},
+		{
+			name: "invalid actions",
+			configuration: `[{"then":[]},` +
+				`{"then":[{"config":{}},{"name":1},{"name":"cache_disable","cfg":{}},null]}]`,
+			expectedErrors: []string{
+				`Element "/0/then": actions must contain at least one action`,
+				`Element "/1/then/0": action must contain "name"`,
+				`Element "/1/then/1/name": action name must be a non-empty string`,
+				`Element "/1/then/2/cfg": unknown action property`,
+				`Element "/1/then/3": action must be an object, got null`,
+			},
+		},
+		{
+			name: "invalid condition types and arguments",
+			configuration: `[{"if":[{"type":"path-regex","regex":".*"},{"type":"path-prefix","path":"/a"},` +
+				`{"type":"scheme","scheme":[]},{"type":"path-suffix","prefix":"a","suffix":"b"},` +
+				`{"type":"header","name":["a",1],"value":true},` +
+				`{"type":"client-ip","ip":["10.0.0.0/8","10.0.0.1","local"]}],` +
+				`"then":[{"name":"cache_disable"}]}]`,
+			expectedErrors: []string{
+				`Element "/0/if/0/type": unknown condition type "path-regex"; must be one of client-ip, country, `,
+				`Element "/0/if/1/path": unknown argument of "path-prefix" condition; allowed arguments are prefix`,
+				`Element "/0/if/1": "path-prefix" condition must contain "prefix"`,
+				`Element "/0/if/2/scheme": argument must be a string or a non-empty array of strings, got array`,
+				`Element "/0/if/3": "path-suffix" condition must contain only one of "prefix" and "suffix"`,
+				`Element "/0/if/4/name/1": argument value must be a string, got number`,
+				`Element "/0/if/4/value": argument must be a string or a non-empty array of strings, got boolean`,
+				`Element "/0/if/5/ip/2": argument value must be an IP address or a CIDR, got "local"`,
+			},
+		},
+		{
+			name: "invalid action configs",
+			configuration: `[{"then":[{"name":"replace_cache"},{"name":"set_var"},` +
+				`{"name":"set_var","config":{"key":"a","val":"b"}},` +
+				`{"name":"response_hdr","config":{"response_hdr_name":"a","response_hdr_value":1}},` +
+				`{"name":"cache_disable","config":{"enabled":true}},{"name":"replace_origin","config":"x"},` +
+				`{"name":"replace_origin","config":{"origins":{}}}]}]`,
+			expectedErrors: []string{
+				`Element "/0/then/0/name": unknown action "replace_cache"; must be one of cache_disable, `,
+				`Element "/0/then/1": "set_var" action must contain "config"`,
+				`Element "/0/then/2/config/val": unknown config property of "set_var" action; allowed properties `,
+				`Element "/0/then/2/config": config of "set_var" action must contain "value"`,
+				`Element "/0/then/3/config/response_hdr_value": config property must be of type string, got number`,
+				`Element "/0/then/4/config/enabled": "cache_disable" action has no config properties`,
+				`Element "/0/then/5/config": action config must be an object, got string`,
+				`Element "/0/then/6/config/origins": config property must be of type array, got object`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("conditional_features").AtName("configuration"),
+				ConfigValue: types.StringValue(tc.configuration),
+			}
+			resp := validator.StringResponse{}
+
+			cdn.ConditionalFeaturesConfigurationValidator{}.ValidateString(t.Context(), req, &resp)
+
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != len(tc.expectedErrors) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tc.expectedErrors), len(errs), errs)
+			}
+
+			for i, expectedError := range tc.expectedErrors {
+				if detail := errs[i].Detail(); !strings.HasPrefix(detail, expectedError) {
+					t.Errorf("expected error starting with %q, got %q", expectedError, detail)
+				}
+			}
+		})
+	}
+}
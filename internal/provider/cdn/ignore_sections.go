@@ -0,0 +1,159 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ConfigValidator = &IgnoredSectionsConfigValidator{}
+
+// ignorableSections returns the attributes which can be listed in "ignore_sections"; they have the same names as
+// the sections of the edit request.
+func ignorableSections() []string {
+	return []string{
+		"cache",
+		"geo_protection",
+		"headers",
+		"hotlink_protection",
+		"https_redirect",
+		"ip_protection",
+		"origin_headers",
+		"query_string",
+		"secure_token",
+		"ssl",
+	}
+}
+
+// sectionAttributes returns the attributes that set the section.
+func sectionAttributes(section string) []string {
+	if section == "secure_token" {
+		return []string{"secure_token", "secure_token_wo"}
+	}
+
+	return []string{section}
+}
+
+func ignoredSections(ctx context.Context, diags *diag.Diagnostics, value types.Set) []string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	sections, _ := util.StringSetToSlice(ctx, diags, path.Root("ignore_sections"), value)
+
+	return sections
+}
+
+// IgnoredSectionsConfigValidator checks that the sections listed in "ignore_sections" aren't configured.
+type IgnoredSectionsConfigValidator struct{}
+
+func (v IgnoredSectionsConfigValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (IgnoredSectionsConfigValidator) MarkdownDescription(context.Context) string {
+	return `Checks that the sections listed in "ignore_sections" aren't configured`
+}
+
+func (IgnoredSectionsConfigValidator) ValidateResource(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	diags := &resp.Diagnostics
+	var value types.Set
+
+	if diags.Append(req.Config.GetAttribute(ctx, path.Root("ignore_sections"), &value)...); diags.HasError() {
+		return
+	}
+
+	for _, section := range ignoredSections(ctx, diags, value) {
+		for _, name := range sectionAttributes(section) {
+			var attrValue attr.Value
+			if diags.Append(req.Config.GetAttribute(ctx, path.Root(name), &attrValue)...); diags.HasError() {
+				return
+			}
+
+			if !attrValue.IsNull() {
+				diags.AddAttributeError(
+					path.Root(name),
+					"Ignored section is configured",
+					fmt.Sprintf(`Attribute %q must not be set; %q is listed in "ignore_sections"`, name, section),
+				)
+			}
+		}
+	}
+}
+
+// planIgnoredSections keeps the state values of the ignored sections in the plan, so the configuration (including
+// the defaults) doesn't cause any changes. New CDNs keep the planned values until they are refreshed.
+func planIgnoredSections(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	var value types.Set
+
+	if diags.Append(req.Plan.GetAttribute(ctx, path.Root("ignore_sections"), &value)...); diags.HasError() {
+		return
+	}
+
+	for _, section := range ignoredSections(ctx, diags, value) {
+		copyAttribute(ctx, diags, req.State, &resp.Plan, path.Root(section))
+	}
+}
+
+func copyAttribute(ctx context.Context, diags *diag.Diagnostics, from tfsdk.State, to *tfsdk.Plan, attrPath path.Path) {
+	var value attr.Value
+	if diags.Append(from.GetAttribute(ctx, attrPath, &value)...); diags.HasError() {
+		return
+	}
+
+	diags.Append(to.SetAttribute(ctx, attrPath, value)...)
+}
+
+// withoutSections removes the given top-level sections from the edit request.
+func withoutSections(
+	diags *diag.Diagnostics,
+	request cdn77.CdnEditJSONRequestBody,
+	sections []string,
+) (cdn77.CdnEditJSONRequestBody, bool) {
+	if len(sections) == 0 {
+		return request, true
+	}
+
+	const errMessage = "Failed to remove ignored sections"
+
+	var requestSections map[string]json.RawMessage
+	if err := util.JsonRoundTrip(request, &requestSections); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return cdn77.CdnEditJSONRequestBody{}, false
+	}
+
+	for name := range requestSections {
+		if slices.Contains(sections, name) {
+			delete(requestSections, name)
+		}
+	}
+
+	var result cdn77.CdnEditJSONRequestBody
+	if err := util.JsonRoundTrip(requestSections, &result); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return cdn77.CdnEditJSONRequestBody{}, false
+	}
+
+	return result, true
+}
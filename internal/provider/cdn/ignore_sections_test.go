@@ -0,0 +1,86 @@
+package cdn_test
+
+import (
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestResource_ModifyPlanIgnoredSections(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	nullRaw := tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)
+	state := tfsdk.State{Schema: s, Raw: nullRaw}
+	plan := tfsdk.Plan{Schema: s, Raw: nullRaw}
+
+	diags := state.SetAttribute(t.Context(), path.Root("origin_headers"), stringMap("X-Security", "panel"))
+	diags.Append(state.SetAttribute(t.Context(), path.Root("note"), types.StringValue("old note"))...)
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("origin_headers"), types.MapNull(types.StringType))...)
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("note"), types.StringValue("new note"))...)
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("ignore_sections"), stringSet("origin_headers"))...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	(&cdn.Resource{}).ModifyPlan(t.Context(), resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var originHeaders types.Map
+	var note types.String
+
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), path.Root("origin_headers"), &originHeaders)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), path.Root("note"), &note)...)
+
+	if !originHeaders.Equal(stringMap("X-Security", "panel")) {
+		t.Errorf("expected ignored section to keep the state value, got %s", originHeaders)
+	}
+
+	if note.ValueString() != "new note" {
+		t.Errorf("expected not ignored attribute to keep the planned value, got %s", note)
+	}
+}
+
+func TestIgnoredSectionsConfigValidator(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	config := tfsdk.Config{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+	state := tfsdk.State(config)
+
+	diags := state.SetAttribute(t.Context(), path.Root("ignore_sections"), stringSet("secure_token", "ssl"))
+	diags.Append(state.SetAttribute(t.Context(), path.Root("secure_token_wo"), types.StringValue("secret"))...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	resp := resource.ValidateConfigResponse{}
+	req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: s, Raw: state.Raw}}
+	cdn.IgnoredSectionsConfigValidator{}.ValidateResource(t.Context(), req, &resp)
+
+	if resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got %v", resp.Diagnostics)
+	}
+
+	expectedDetail := `Attribute "secure_token_wo" must not be set; "secure_token" is listed in "ignore_sections"`
+	if detail := resp.Diagnostics.Errors()[0].Detail(); detail != expectedDetail {
+		t.Errorf("expected %q, got %q", expectedDetail, detail)
+	}
+}
+
+func stringSet(values ...string) types.Set {
+	elements := make([]attr.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+
+	return types.SetValueMust(types.StringType, elements)
+}
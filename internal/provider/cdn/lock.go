@@ -0,0 +1,26 @@
+package cdn
+
+import "sync"
+
+const (
+	cdnSectionCnames              = "cnames"
+	cdnSectionConditionalFeatures = "conditional_features"
+)
+
+type cdnLockKey struct {
+	cdnId   int64
+	section string
+}
+
+//nolint:gochecknoglobals // the locks must be shared by all resource instances of the provider
+var cdnLocks sync.Map
+
+// lockCdn serializes read-modify-write cycles of a section of the CDN (e.g. its CNAMEs), so resources sharing
+// the section can be applied in parallel. It returns the function releasing the lock.
+func lockCdn(cdnId int64, section string) func() {
+	value, _ := cdnLocks.LoadOrStore(cdnLockKey{cdnId: cdnId, section: section}, &sync.Mutex{})
+	mutex, _ := value.(*sync.Mutex)
+	mutex.Lock()
+
+	return mutex.Unlock
+}
@@ -0,0 +1,454 @@
+package cdn
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// managedRuleVariable is the variable set by the marker action of rules managed by "cdn77_cdn_rule" resources;
+// its value is the priority of the rule.
+const managedRuleVariable = "terraform_rule"
+
+type managedRuleMarker struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func managedRulePriority(rule jsonRule) (int64, bool) {
+	if rule.Then == nil {
+		return 0, false
+	}
+
+	for _, action := range *rule.Then {
+		if priority, ok := managedRuleMarkerPriority(action); ok {
+			return priority, true
+		}
+	}
+
+	return 0, false
+}
+
+func managedRuleMarkerPriority(action jsonAction) (int64, bool) {
+	if !setsManagedRuleVariable(action) {
+		return 0, false
+	}
+
+	var marker managedRuleMarker
+	if json.Unmarshal(*action.Config, &marker) != nil {
+		return 0, false
+	}
+
+	priority, err := strconv.ParseInt(marker.Value, 10, 64)
+
+	return priority, err == nil
+}
+
+// setsManagedRuleVariable reports whether the action sets the variable of the marker action (no matter the value).
+func setsManagedRuleVariable(action jsonAction) bool {
+	if action.Name == nil || *action.Name != "set_var" || action.Config == nil {
+		return false
+	}
+
+	var marker managedRuleMarker
+
+	return json.Unmarshal(*action.Config, &marker) == nil && marker.Key == managedRuleVariable
+}
+
+// isManagedRuleMarker reports whether the action (decoded from JSON) is the marker of a managed rule.
+func isManagedRuleMarker(value any) bool {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	var action jsonAction
+	if json.Unmarshal(raw, &action) != nil {
+		return false
+	}
+
+	_, ok := managedRuleMarkerPriority(action)
+
+	return ok
+}
+
+func withManagedRuleMarker(rule jsonRule, priority int64) (jsonRule, error) {
+	config, err := json.Marshal(managedRuleMarker{Key: managedRuleVariable, Value: strconv.FormatInt(priority, 10)})
+	if err != nil {
+		return jsonRule{}, err
+	}
+
+	var actions []jsonAction
+	if rule.Then != nil {
+		actions = slices.Clone(*rule.Then)
+	}
+
+	rawConfig := json.RawMessage(config)
+	actions = append(actions, jsonAction{Name: util.Pointer("set_var"), Config: &rawConfig})
+
+	return jsonRule{If: rule.If, Then: &actions}, nil
+}
+
+func withoutManagedRuleMarker(rule jsonRule) jsonRule {
+	if rule.Then == nil {
+		return rule
+	}
+
+	actions := slices.DeleteFunc(slices.Clone(*rule.Then), func(action jsonAction) bool {
+		_, ok := managedRuleMarkerPriority(action)
+
+		return ok
+	})
+
+	return jsonRule{If: rule.If, Then: &actions}
+}
+
+// orderRules puts the rules not managed by "cdn77_cdn_rule" resources first (in their original order), followed by
+// the managed rules ordered by their priority.
+func orderRules(rules []jsonRule) []jsonRule {
+	type managedRule struct {
+		priority int64
+		rule     jsonRule
+	}
+
+	var managedRules []managedRule
+	ordered := make([]jsonRule, 0, len(rules))
+
+	for _, rule := range rules {
+		if priority, ok := managedRulePriority(rule); ok {
+			managedRules = append(managedRules, managedRule{priority: priority, rule: rule})
+
+			continue
+		}
+
+		ordered = append(ordered, rule)
+	}
+
+	slices.SortStableFunc(managedRules, func(a, b managedRule) int { return cmp.Compare(a.priority, b.priority) })
+
+	for _, managed := range managedRules {
+		ordered = append(ordered, managed.rule)
+	}
+
+	return ordered
+}
+
+// splitManagedRules returns the rules not managed by "cdn77_cdn_rule" resources and the managed ones.
+func splitManagedRules(rules []jsonRule) ([]jsonRule, []jsonRule) {
+	var unmanaged, managed []jsonRule
+
+	for _, rule := range rules {
+		if _, ok := managedRulePriority(rule); ok {
+			managed = append(managed, rule)
+		} else {
+			unmanaged = append(unmanaged, rule)
+		}
+	}
+
+	return unmanaged, managed
+}
+
+func findManagedRule(rules []jsonRule, priority int64) int {
+	return slices.IndexFunc(rules, func(rule jsonRule) bool {
+		p, ok := managedRulePriority(rule)
+
+		return ok && p == priority
+	})
+}
+
+// jsonRulesFromApi converts the configuration read from the API; raw JSON values of the result are compact and
+// empty action configs are always objects.
+func jsonRulesFromApi(configuration *[]cdn77.ConditionalFeatureConfiguration) ([]jsonRule, error) {
+	if configuration == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	if raw, err = normalizeConditionalFeaturesEmptyConfigArray(raw); err != nil {
+		return nil, fmt.Errorf("failed to normalize configuration: %w", err)
+	}
+
+	var rules []jsonRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	return rules, nil
+}
+
+func jsonRulesToApi(rules []jsonRule) (*[]cdn77.ConditionalFeatureConfiguration, error) {
+	if rules == nil {
+		rules = []jsonRule{}
+	}
+
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	configuration := []cdn77.ConditionalFeatureConfiguration{}
+	if err := json.Unmarshal(raw, &configuration); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	return &configuration, nil
+}
+
+// fetchConditionalFeatures returns the current conditional features of the CDN; the result is nil (without any error)
+// if the CDN doesn't exist.
+func fetchConditionalFeatures(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	cdnId int64,
+) (*cdn77.ConditionalFeatures, bool) {
+	const errMessage = "Failed to fetch conditional features of CDN"
+
+	response, err := client.CdnDetailWithResponse(ctx, int(cdnId))
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		return nil, true
+	}
+
+	var cf *cdn77.ConditionalFeatures
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(detail *cdn77.Cdn) {
+		cf = detail.ConditionalFeatures
+		if cf == nil {
+			cf = &cdn77.ConditionalFeatures{}
+		}
+	})
+
+	return cf, !diags.HasError()
+}
+
+// keepExternalRules adds the rules managed by "cdn77_cdn_rule" resources to the edited conditional features, so
+// the edit doesn't remove them. The secrets are sent only if they differ from ownSecrets (the secrets previously set
+// by the CDN resource); the secrets unknown to the CDN resource are added to them then, while the previous own
+// secrets are removed unless they are edited.
+func keepExternalRules(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	cdnId int64,
+	ownSecrets map[string]string,
+	cf *cdn77.ConditionalFeatures,
+) bool {
+	const errMessage = "Failed to keep external conditional feature rules"
+
+	current, ok := fetchConditionalFeatures(ctx, client, diags, cdnId)
+	if !ok || current == nil {
+		return ok
+	}
+
+	if cf.Configuration != nil {
+		currentRules, err := jsonRulesFromApi(current.Configuration)
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
+
+			return false
+		}
+
+		rules, err := jsonRulesFromApi(cf.Configuration)
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
+
+			return false
+		}
+
+		_, managedRules := splitManagedRules(currentRules)
+
+		if cf.Configuration, err = jsonRulesToApi(orderRules(append(rules, managedRules...))); err != nil {
+			diags.AddError(errMessage, err.Error())
+
+			return false
+		}
+	}
+
+	if cf.Secrets != nil && maps.Equal(*cf.Secrets, ownSecrets) {
+		cf.Secrets = nil
+	}
+
+	if cf.Secrets != nil && current.Secrets != nil {
+		secrets := make(map[string]string, len(*current.Secrets))
+
+		for name, value := range *current.Secrets {
+			if _, ok := ownSecrets[name]; !ok {
+				secrets[name] = value
+			}
+		}
+
+		for name, value := range *cf.Secrets {
+			secrets[name] = value
+		}
+
+		cf.Secrets = &secrets
+	}
+
+	return true
+}
+
+// withoutExternalRules returns the conditional features without the rules managed by "cdn77_cdn_rule" resources and
+// with only the given secrets.
+func withoutExternalRules(
+	diags *diag.Diagnostics,
+	cf *cdn77.ConditionalFeatures,
+	ownSecrets []string,
+) *cdn77.ConditionalFeatures {
+	rules, err := jsonRulesFromApi(cf.Configuration)
+	if err != nil {
+		diags.AddError("Failed to remove external conditional feature rules", err.Error())
+
+		return nil
+	}
+
+	unmanagedRules, _ := splitManagedRules(rules)
+
+	configuration, err := jsonRulesToApi(unmanagedRules)
+	if err != nil {
+		diags.AddError("Failed to remove external conditional feature rules", err.Error())
+
+		return nil
+	}
+
+	result := &cdn77.ConditionalFeatures{Configuration: configuration}
+
+	if cf.Secrets != nil {
+		secrets := make(map[string]string)
+
+		for _, name := range ownSecrets {
+			if value, ok := (*cf.Secrets)[name]; ok {
+				secrets[name] = value
+			}
+		}
+
+		result.Secrets = &secrets
+	}
+
+	return result
+}
+
+// ownSecretNames returns names of the secrets set by the CDN resource.
+func ownSecretNames(data Model) []string {
+	return slices.Collect(maps.Keys(ownSecrets(data)))
+}
+
+// ownSecrets returns the secrets set by the CDN resource.
+func ownSecrets(data Model) map[string]string {
+	if data.ConditionalFeatures == nil {
+		return nil
+	}
+
+	secrets := map[string]string{}
+
+	for name, value := range data.ConditionalFeatures.Secrets.Elements() {
+		if value, ok := value.(types.String); ok {
+			secrets[name] = value.ValueString()
+		}
+	}
+
+	return secrets
+}
+
+var (
+	_ validator.String = ManagedRuleVariableValidator{}
+	_ validator.Object = ManagedRuleVariableValidator{}
+)
+
+// ManagedRuleVariableValidator rejects "set_var" actions setting the variable reserved for the marker action of rules
+// managed by "cdn77_cdn_rule" resources; such rules would be mistaken for the managed ones. It validates both the JSON
+// configuration and the actions of the rules.
+type ManagedRuleVariableValidator struct{}
+
+func (ManagedRuleVariableValidator) Description(context.Context) string {
+	return fmt.Sprintf("actions must not set the reserved variable %q", managedRuleVariable)
+}
+
+func (v ManagedRuleVariableValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ManagedRuleVariableValidator) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	// Invalid configuration is reported by its validator.
+	var rules []jsonRule
+	if json.Unmarshal([]byte(req.ConfigValue.ValueString()), &rules) != nil {
+		return
+	}
+
+	for i, rule := range rules {
+		if rule.Then == nil {
+			continue
+		}
+
+		for j, action := range *rule.Then {
+			if setsManagedRuleVariable(action) {
+				pointer := jsonPointer(jsonPointer(jsonPointer("", i), "then"), j)
+				resp.Diagnostics.AddAttributeError(
+					req.Path,
+					"Invalid conditional features configuration",
+					fmt.Sprintf("Element %q: %s", pointer, v.reservedMessage()),
+				)
+			}
+		}
+	}
+}
+
+func (v ManagedRuleVariableValidator) ValidateObject(
+	_ context.Context,
+	req validator.ObjectRequest,
+	resp *validator.ObjectResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	name, ok := req.ConfigValue.Attributes()["name"].(types.String)
+	if !ok || name.IsNull() || name.IsUnknown() {
+		return
+	}
+
+	config, ok := req.ConfigValue.Attributes()["config"].(util.JsonValue)
+	if !ok || config.IsNull() || config.IsUnknown() {
+		return
+	}
+
+	rawConfig := json.RawMessage(config.ValueString())
+	if setsManagedRuleVariable(jsonAction{Name: name.ValueStringPointer(), Config: &rawConfig}) {
+		resp.Diagnostics.AddAttributeError(req.Path.AtName("config"), "Invalid action", v.reservedMessage())
+	}
+}
+
+func (ManagedRuleVariableValidator) reservedMessage() string {
+	return fmt.Sprintf(
+		`the variable %q is reserved for the marker action of rules managed by "cdn77_cdn_rule" resources`,
+		managedRuleVariable,
+	)
+}
@@ -15,6 +15,14 @@ import (
 
 type Reader struct{}
 
+func NewDataSourceReader() util.Reader {
+	toModel := func(data DataSourceModel) Model {
+		return Model{Id: data.Id}
+	}
+
+	return util.NewDataSourceReader(&Reader{}, toModel, newDataSourceModel)
+}
+
 func (*Reader) ErrMessage() string {
 	return "Failed to fetch CDN"
 }
@@ -45,7 +53,7 @@ func (r *Reader) Process(ctx context.Context, model Model, cdn *cdn77.Cdn, diags
 		}
 	}
 
-	cnames := r.getCnamesSet(ctx, cdn, diags)
+	cnames := r.getCnamesSet(ctx, model, cdn, diags)
 
 	geoProtectionCountries := types.SetNull(types.StringType)
 	if cdn.GeoProtection.Countries != nil {
@@ -74,6 +82,12 @@ func (r *Reader) Process(ctx context.Context, model Model, cdn *cdn77.Cdn, diags
 
 	conditionalFeatures := r.readConditionalFeatures(ctx, model, cdn, diags)
 
+	// Models created by data sources don't have the element type set.
+	ignoreSections := model.IgnoreSections
+	if ignoreSections.ElementType(ctx) == nil {
+		ignoreSections = types.SetNull(types.StringType)
+	}
+
 	if diags.HasError() {
 		return model
 	}
@@ -116,7 +130,7 @@ func (r *Reader) Process(ctx context.Context, model Model, cdn *cdn77.Cdn, diags
 			Type: types.StringValue(string(cdn.IpProtection.Type)),
 		},
 		Mp4PseudoStreamingEnabled: types.BoolPointerValue(cdn.Mp4PseudoStreaming.Enabled),
-		Note:                      util.NullableToStringValue(cdn.Note),
+		Note:                      util.NullableToStringValue(util.WithoutIdempotencyMarkers(cdn.Note)),
 		OriginHeaders:             originHeaders,
 		QueryString: &ModelQueryString{
 			Parameters: queryStringParameters,
@@ -124,22 +138,36 @@ func (r *Reader) Process(ctx context.Context, model Model, cdn *cdn77.Cdn, diags
 		},
 		RateLimitEnabled: types.BoolValue(cdn.RateLimit.Enabled),
 		SecureToken: &ModelSecureToken{
-			Token: types.StringPointerValue(cdn.SecureToken.Token),
-			Type:  types.StringValue(string(cdn.SecureToken.Type)),
-		},
-		Ssl: &ModelSsl{
-			Type:  types.StringValue(string(cdn.Ssl.Type)),
-			SslId: types.StringPointerValue(cdn.Ssl.SslId),
+			Token: util.If(
+				model.SecureTokenWoVersion.IsNull(),
+				types.StringPointerValue(cdn.SecureToken.Token),
+				types.StringNull(),
+			),
+			Type: types.StringValue(string(cdn.SecureToken.Type)),
 		},
-		ConditionalFeatures: conditionalFeatures,
+		SecureTokenWo:           types.StringNull(),
+		SecureTokenWoVersion:    model.SecureTokenWoVersion,
+		Ssl:                     readSsl(model, cdn),
+		ConditionalFeatures:     conditionalFeatures,
+		ConditionalFeaturesLint: model.ConditionalFeaturesLint,
+		IgnoreExternalRules:     model.IgnoreExternalRules,
+		IgnoreExternalCnames:    model.IgnoreExternalCnames,
+		IgnoreSections:          ignoreSections,
+		OnCreateFailure:         model.OnCreateFailure,
+		DeletionProtection:      model.DeletionProtection,
 	}
 }
 
-func (*Reader) getCnamesSet(ctx context.Context, cdn *cdn77.Cdn, diags *diag.Diagnostics) types.Set {
-	cnames := make([]string, len(cdn.Cnames))
+func (*Reader) getCnamesSet(ctx context.Context, model Model, cdn *cdn77.Cdn, diags *diag.Diagnostics) types.Set {
+	own := ownCnames(model)
+	cnames := make([]string, 0, len(cdn.Cnames))
 
-	for i, c := range cdn.Cnames {
-		cnames[i] = c.Cname
+	for _, c := range cdn.Cnames {
+		if model.IgnoreExternalCnames.ValueBool() && !containsCname(own, c.Cname) {
+			continue
+		}
+
+		cnames = append(cnames, c.Cname)
 	}
 
 	return util.SetValueFrom(ctx, diags, types.StringType, cnames)
@@ -157,7 +185,26 @@ func (r *Reader) readConditionalFeatures(
 
 	src := c.ConditionalFeatures
 
+	if state.IgnoreExternalRules.ValueBool() {
+		if src = withoutExternalRules(diags, src, ownSecretNames(state)); src == nil {
+			return nil
+		}
+	}
+
 	configuration := r.readConditionalFeaturesConfiguration(diags, src)
+	rule := ruleListFromConfiguration(ctx, diags, configuration)
+
+	// Secrets managed by the write-only attribute must never be written to the state.
+	if state.ConditionalFeatures != nil && !state.ConditionalFeatures.SecretsWoVersion.IsNull() {
+		return &ModelConditionalFeatures{
+			Configuration:    configuration,
+			Rule:             rule,
+			Secrets:          types.MapNull(types.StringType),
+			SecretsWo:        types.MapNull(types.StringType),
+			SecretsWoVersion: state.ConditionalFeatures.SecretsWoVersion,
+		}
+	}
+
 	secrets := r.readConditionalFeaturesSecrets(ctx, diags, src, state.ConditionalFeatures)
 
 	if diags.HasError() {
@@ -172,41 +219,44 @@ func (r *Reader) readConditionalFeatures(
 	}
 
 	return &ModelConditionalFeatures{
-		Configuration: configuration,
-		Secrets:       secrets,
+		Configuration:    configuration,
+		Rule:             rule,
+		Secrets:          secrets,
+		SecretsWo:        types.MapNull(types.StringType),
+		SecretsWoVersion: types.Int64Null(),
 	}
 }
 
 func (*Reader) readConditionalFeaturesConfiguration(
 	diags *diag.Diagnostics,
 	src *cdn77.ConditionalFeatures,
-) types.String {
+) util.JsonValue {
 	if src.Configuration == nil || len(*src.Configuration) == 0 {
-		return types.StringNull()
+		return util.NewJsonNull()
 	}
 
 	raw, err := json.Marshal(src.Configuration)
 	if err != nil {
 		diags.AddError("Failed to marshal conditional_features.configuration", err.Error())
 
-		return types.StringNull()
+		return util.NewJsonNull()
 	}
 
 	raw, err = normalizeConditionalFeaturesEmptyConfigArray(raw)
 	if err != nil {
 		diags.AddError("Failed to normalize conditional_features.configuration", err.Error())
 
-		return types.StringNull()
+		return util.NewJsonNull()
 	}
 
 	canon, err := canonicalizeJSON(string(raw))
 	if err != nil {
 		diags.AddError("Failed to canonicalize conditional_features.configuration", err.Error())
 
-		return types.StringNull()
+		return util.NewJsonNull()
 	}
 
-	return types.StringValue(canon)
+	return util.NewJsonValue(canon)
 }
 
 func (*Reader) readConditionalFeaturesSecrets(
@@ -274,3 +324,50 @@ func normalizeConditionalFeaturesEmptyConfigArray(raw []byte) ([]byte, error) {
 
 	return normalizedJSON, nil
 }
+
+// readSsl keeps "ssl_id" set to "auto" while the CDN uses an SNI certificate.
+func readSsl(model Model, cdn *cdn77.Cdn) *ModelSsl {
+	sslId := types.StringPointerValue(cdn.Ssl.SslId)
+	isAuto := model.Ssl != nil && model.Ssl.SslId.ValueString() == autoSslId
+
+	if isAuto && cdn.Ssl.Type == cdn77.SNI {
+		return &ModelSsl{Type: types.StringValue(string(cdn.Ssl.Type)), SslId: model.Ssl.SslId, ResolvedSslId: sslId}
+	}
+
+	return &ModelSsl{Type: types.StringValue(string(cdn.Ssl.Type)), SslId: sslId, ResolvedSslId: sslId}
+}
+
+func newDataSourceModel(model Model) DataSourceModel {
+	var conditionalFeatures *DataSourceModelConditionalFeatures
+	if cf := model.ConditionalFeatures; cf != nil {
+		conditionalFeatures = &DataSourceModelConditionalFeatures{
+			Configuration: cf.Configuration,
+			Rule:          cf.Rule,
+			Secrets:       cf.Secrets,
+		}
+	}
+
+	return DataSourceModel{
+		Id:                        model.Id,
+		Label:                     model.Label,
+		OriginId:                  model.OriginId,
+		CreationTime:              model.CreationTime,
+		Url:                       model.Url,
+		Stream:                    model.Stream,
+		Cache:                     model.Cache,
+		Cnames:                    model.Cnames,
+		GeoProtection:             model.GeoProtection,
+		Headers:                   model.Headers,
+		HotlinkProtection:         model.HotlinkProtection,
+		HttpsRedirect:             model.HttpsRedirect,
+		IpProtection:              model.IpProtection,
+		Mp4PseudoStreamingEnabled: model.Mp4PseudoStreamingEnabled,
+		Note:                      model.Note,
+		OriginHeaders:             model.OriginHeaders,
+		QueryString:               model.QueryString,
+		RateLimitEnabled:          model.RateLimitEnabled,
+		SecureToken:               model.SecureToken,
+		Ssl:                       model.Ssl,
+		ConditionalFeatures:       conditionalFeatures,
+	}
+}
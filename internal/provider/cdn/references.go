@@ -0,0 +1,169 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// validateReferences checks that the planned Origin and SSL certificate exist and that the certificate covers all
+// CNAMEs. Unknown values and references which didn't change since the last apply aren't checked.
+func (r *Resource) validateReferences(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if r.BaseResource == nil || r.Client == nil {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	originIdPath := path.Root("origin_id")
+	sslIdPath := path.Root("ssl").AtName("ssl_id")
+	cnamesPath := path.Root("cnames")
+
+	var originId, stateOriginId, sslId, stateSslId types.String
+	var cnames, stateCnames types.Set
+
+	diags.Append(req.Plan.GetAttribute(ctx, originIdPath, &originId)...)
+	diags.Append(req.Plan.GetAttribute(ctx, sslIdPath, &sslId)...)
+	diags.Append(req.Plan.GetAttribute(ctx, cnamesPath, &cnames)...)
+
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.GetAttribute(ctx, originIdPath, &stateOriginId)...)
+		diags.Append(req.State.GetAttribute(ctx, sslIdPath, &stateSslId)...)
+		diags.Append(req.State.GetAttribute(ctx, cnamesPath, &stateCnames)...)
+	}
+
+	if diags.HasError() {
+		return
+	}
+
+	sslChanged := !sslId.Equal(stateSslId) || !cnames.Equal(stateCnames)
+
+	// Automatically selected certificates always cover all CNAMEs.
+	isAuto := sslId.ValueString() == autoSslId
+
+	if !sslId.IsNull() && !sslId.IsUnknown() && !isAuto && !cnames.IsUnknown() && sslChanged {
+		if cnameList, ok := util.StringSetToSlice(ctx, diags, cnamesPath, cnames); ok {
+			r.validateSslId(ctx, diags, sslIdPath, sslId.ValueString(), cnameList)
+		}
+	}
+
+	if isKnownChange(originId, stateOriginId) {
+		r.validateOriginId(ctx, diags, originIdPath, originId.ValueString())
+	}
+}
+
+func isKnownChange(value types.String, stateValue types.String) bool {
+	return !value.IsNull() && !value.IsUnknown() && !value.Equal(stateValue)
+}
+
+func (r *Resource) validateOriginId(ctx context.Context, diags *diag.Diagnostics, p path.Path, originId string) {
+	const errMessage = "Failed to validate Origin ID"
+
+	response, err := r.Client.OriginListWithResponse(ctx)
+	if err != nil {
+		diags.AddAttributeError(p, errMessage, err.Error())
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *cdn77.OriginList) {
+		for _, item := range *list {
+			if originItemId(item) == originId {
+				return
+			}
+		}
+
+		diags.AddAttributeError(p, "Origin not found", fmt.Sprintf("Origin with ID %q doesn't exist", originId))
+	})
+}
+
+func originItemId(item cdn77.OriginList_Item) string {
+	origin, err := item.ValueByDiscriminator()
+	if err != nil {
+		return ""
+	}
+
+	switch o := origin.(type) {
+	case cdn77.S3OriginDetail:
+		return o.Id
+	case cdn77.ObjectStorageOriginDetail:
+		return o.Id
+	case cdn77.StorageOriginDetail:
+		return o.Id
+	case cdn77.UrlOriginDetail:
+		return o.Id
+	default:
+		return ""
+	}
+}
+
+func (r *Resource) validateSslId(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	p path.Path,
+	sslId string,
+	cnames []string,
+) {
+	const errMessage = "Failed to validate SSL certificate ID"
+
+	response, err := r.Client.SslSniDetailWithResponse(ctx, sslId)
+	if err != nil {
+		diags.AddAttributeError(p, errMessage, err.Error())
+
+		return
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		diags.AddAttributeError(
+			p,
+			"SSL certificate not found",
+			fmt.Sprintf("SSL certificate with ID %q doesn't exist", sslId),
+		)
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(ssl *cdn77.Ssl) {
+		for _, cname := range cnames {
+			if !slices.ContainsFunc(ssl.Cnames, func(subject string) bool { return coversHostname(subject, cname) }) {
+				diags.AddAttributeWarning(
+					path.Root("cnames"),
+					"CNAME not covered by SSL certificate",
+					fmt.Sprintf(
+						"CNAME %q isn't covered by any subject of SSL certificate %q (%s)",
+						cname,
+						sslId,
+						strings.Join(ssl.Cnames, ", "),
+					),
+				)
+			}
+		}
+	})
+}
+
+// coversHostname reports whether the certificate subject matches the hostname; a wildcard matches exactly one label.
+func coversHostname(subject string, hostname string) bool {
+	subject = strings.ToLower(strings.TrimSuffix(subject, "."))
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+
+	suffix, isWildcard := strings.CutPrefix(subject, "*.")
+	if !isWildcard {
+		return subject == hostname
+	}
+
+	label, rest, found := strings.Cut(hostname, ".")
+
+	return found && label != "" && rest == suffix
+}
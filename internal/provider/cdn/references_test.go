@@ -0,0 +1,146 @@
+package cdn_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type referencesClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	originId    string
+	sslSubjects []string
+	ssls        cdn77.SslList
+}
+
+func (c referencesClient) OriginListWithResponse(
+	context.Context,
+	...cdn77.RequestEditorFn,
+) (*cdn77.OriginListResponse, error) {
+	var item cdn77.OriginList_Item
+	if err := item.FromUrlOriginDetail(cdn77.UrlOriginDetail{Id: c.originId}); err != nil {
+		return nil, err
+	}
+
+	return &cdn77.OriginListResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &cdn77.OriginList{item},
+	}, nil
+}
+
+func (c referencesClient) SslSniDetailWithResponse(
+	_ context.Context,
+	id string,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.SslSniDetailResponse, error) {
+	return &cdn77.SslSniDetailResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &cdn77.Ssl{Id: id, Cnames: c.sslSubjects},
+	}, nil
+}
+
+func (c referencesClient) SslSniListWithResponse(
+	context.Context,
+	...cdn77.RequestEditorFn,
+) (*cdn77.SslSniListResponse, error) {
+	return &cdn77.SslSniListResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &c.ssls,
+	}, nil
+}
+
+func TestResource_ModifyPlanReferences(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+
+	cnames := stringSet("a.example.com", "b.c.example.com", "example.com", "www.example.org")
+	diags := plan.SetAttribute(t.Context(), path.Root("origin_id"), types.StringValue("missing-origin"))
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("ssl").AtName("ssl_id"), types.StringValue("ssl-id"))...)
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("cnames"), cnames)...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	r := &cdn.Resource{BaseResource: util.NewBaseResource("cdn", cdn.CreateResourceSchema, nil)}
+	r.Client = referencesClient{originId: "existing-origin", sslSubjects: []string{"*.example.com", "WWW.example.org"}}
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(t.Context(), resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+
+	if resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got %v", resp.Diagnostics)
+	}
+
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Origin not found" {
+		t.Errorf("expected missing Origin error, got %q", summary)
+	}
+
+	warnings := resp.Diagnostics.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", warnings)
+	}
+
+	const subjects = `SSL certificate "ssl-id" (*.example.com, WWW.example.org)`
+	expectedDetails := []string{
+		`CNAME "b.c.example.com" isn't covered by any subject of ` + subjects,
+		`CNAME "example.com" isn't covered by any subject of ` + subjects,
+	}
+
+	for i, warning := range warnings {
+		if warning.Detail() != expectedDetails[i] {
+			t.Errorf("expected warning %q, got %q", expectedDetails[i], warning.Detail())
+		}
+	}
+}
+
+func TestResource_ModifyPlanAutoSsl(t *testing.T) {
+	s := cdn.CreateResourceSchema()
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+
+	diags := plan.SetAttribute(t.Context(), path.Root("ssl").AtName("type"), types.StringValue(string(cdn77.SNI)))
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("ssl").AtName("ssl_id"), types.StringValue("auto"))...)
+	diags.Append(plan.SetAttribute(t.Context(), path.Root("cnames"), stringSet("a.example.com", "example.com"))...)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	now := time.Now()
+	covering := []string{"example.com", "*.example.com"}
+	r := &cdn.Resource{BaseResource: util.NewBaseResource("cdn", cdn.CreateResourceSchema, nil)}
+	r.Client = referencesClient{ssls: cdn77.SslList{
+		{Id: "expired", Cnames: covering, ExpiresAt: now.Add(-time.Hour)},
+		{Id: "latest", Cnames: covering, ExpiresAt: now.Add(90 * 24 * time.Hour)},
+		{Id: "older", Cnames: covering, ExpiresAt: now.Add(30 * 24 * time.Hour)},
+		{Id: "not-covering", Cnames: []string{"*.example.com"}, ExpiresAt: now.Add(365 * 24 * time.Hour)},
+	}}
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(t.Context(), resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var resolved types.String
+	resolvedPath := path.Root("ssl").AtName("resolved_ssl_id")
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), resolvedPath, &resolved)...)
+
+	if resolved.ValueString() != "latest" {
+		t.Errorf(`expected "latest" certificate, got %s`, resolved)
+	}
+}
@@ -0,0 +1,323 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithConfigure   = &RuleResource{}
+	_ resource.ResourceWithImportState = &RuleResource{}
+)
+
+type RuleResource struct {
+	*util.BaseResource
+}
+
+func (r *RuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	diags := &resp.Diagnostics
+	var data RuleModel
+
+	if diags.Append(req.Plan.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(ruleId(data.CdnId.ValueInt64(), data.Priority.ValueInt64()))
+
+	const errMessage = "Failed to create CDN rule"
+
+	modifyRules := func(rules []jsonRule) ([]jsonRule, bool) {
+		if findManagedRule(rules, data.Priority.ValueInt64()) != -1 {
+			diags.AddAttributeError(
+				path.Root("priority"),
+				errMessage,
+				fmt.Sprintf("Rule with priority %d already exists", data.Priority.ValueInt64()),
+			)
+
+			return nil, false
+		}
+
+		return mergeRule(diags, errMessage, rules, data)
+	}
+	var modifySecrets func(secrets map[string]string) bool
+	if len(data.Secrets.Elements()) != 0 {
+		modifySecrets = func(secrets map[string]string) bool {
+			return setRuleSecrets(ctx, diags, secrets, nil, data.Secrets)
+		}
+	}
+
+	ok := r.editRules(ctx, diags, errMessage, data.CdnId.ValueInt64(), false, modifyRules, modifySecrets)
+	if ok {
+		diags.Append(resp.State.Set(ctx, data)...)
+	}
+}
+
+func (r *RuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	diags := &resp.Diagnostics
+	var data RuleModel
+
+	if diags.Append(req.State.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	cf, ok := fetchConditionalFeatures(ctx, r.Client, diags, data.CdnId.ValueInt64())
+	if !ok {
+		return
+	}
+
+	if cf == nil {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	const errMessage = "Failed to read CDN rule"
+
+	rules, err := jsonRulesFromApi(cf.Configuration)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	i := findManagedRule(rules, data.Priority.ValueInt64())
+	if i == -1 {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	rule, err := ruleFromJson(withoutManagedRuleMarker(rules[i]))
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	// Secrets are kept as they are in the state; the values returned by the API aren't necessarily the ones set.
+	data.If = rule.If
+	data.Then = rule.Then
+
+	diags.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *RuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	diags := &resp.Diagnostics
+	var data, state RuleModel
+
+	if diags.Append(req.Plan.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	if diags.Append(req.State.Get(ctx, &state)...); diags.HasError() {
+		return
+	}
+
+	const errMessage = "Failed to update CDN rule"
+
+	modifyRules := func(rules []jsonRule) ([]jsonRule, bool) {
+		return mergeRule(diags, errMessage, rules, data)
+	}
+	var modifySecrets func(secrets map[string]string) bool
+	if !data.Secrets.Equal(state.Secrets) {
+		modifySecrets = func(secrets map[string]string) bool {
+			return setRuleSecrets(ctx, diags, secrets, state.Secrets.Elements(), data.Secrets)
+		}
+	}
+
+	ok := r.editRules(ctx, diags, errMessage, data.CdnId.ValueInt64(), false, modifyRules, modifySecrets)
+	if ok {
+		diags.Append(resp.State.Set(ctx, data)...)
+	}
+}
+
+func (r *RuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	diags := &resp.Diagnostics
+	var data RuleModel
+
+	if diags.Append(req.State.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	const errMessage = "Failed to delete CDN rule"
+
+	modifyRules := func(rules []jsonRule) ([]jsonRule, bool) {
+		return orderRules(removeManagedRule(rules, data.Priority.ValueInt64())), true
+	}
+	var modifySecrets func(secrets map[string]string) bool
+	if len(data.Secrets.Elements()) != 0 {
+		modifySecrets = func(secrets map[string]string) bool {
+			return setRuleSecrets(ctx, diags, secrets, data.Secrets.Elements(), types.MapNull(types.StringType))
+		}
+	}
+
+	r.editRules(ctx, diags, errMessage, data.CdnId.ValueInt64(), true, modifyRules, modifySecrets)
+}
+
+func (*RuleResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	cdnIdPart, priorityPart, found := strings.Cut(req.ID, "/")
+	cdnId, cdnIdErr := strconv.ParseInt(cdnIdPart, 10, 64)
+	priority, priorityErr := strconv.ParseInt(priorityPart, 10, 64)
+
+	if !found || cdnIdErr != nil || priorityErr != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: <cdn_id>/<priority>, got: %q", req.ID),
+		)
+
+		return
+	}
+
+	diags := &resp.Diagnostics
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("id"), ruleId(cdnId, priority))...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("cdn_id"), cdnId)...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("priority"), priority)...)
+}
+
+// editRules modifies the rules and secrets of the CDN while holding the lock of its conditional features, so rules
+// of the same CDN can be applied in parallel. Secrets are sent only if modifySecrets is set (i.e. the secrets of the
+// rule changed), so the secrets of the CDN are otherwise left untouched. Nothing is done if the CDN doesn't exist
+// anymore and the rule is being deleted.
+func (r *RuleResource) editRules(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	errMessage string,
+	cdnId int64,
+	deleting bool,
+	modifyRules func(rules []jsonRule) ([]jsonRule, bool),
+	modifySecrets func(secrets map[string]string) bool,
+) bool {
+	defer lockCdn(cdnId, cdnSectionConditionalFeatures)()
+
+	cf, ok := fetchConditionalFeatures(ctx, r.Client, diags, cdnId)
+	if !ok {
+		return false
+	}
+
+	if cf == nil {
+		if deleting {
+			return true
+		}
+
+		diags.AddAttributeError(path.Root("cdn_id"), errMessage, fmt.Sprintf("CDN %d not found", cdnId))
+
+		return false
+	}
+
+	rules, err := jsonRulesFromApi(cf.Configuration)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return false
+	}
+
+	if rules, ok = modifyRules(rules); !ok {
+		return false
+	}
+
+	configuration, err := jsonRulesToApi(rules)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return false
+	}
+
+	request := cdn77.CdnEditJSONRequestBody{
+		ConditionalFeatures: &cdn77.ConditionalFeatures{Configuration: configuration},
+	}
+
+	if modifySecrets != nil {
+		secrets := map[string]string{}
+		if cf.Secrets != nil {
+			secrets = *cf.Secrets
+		}
+
+		if !modifySecrets(secrets) {
+			return false
+		}
+
+		request.ConditionalFeatures.Secrets = &secrets
+	}
+
+	response, err := r.Client.CdnEditWithResponse(ctx, int(cdnId), request)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return false
+	}
+
+	util.ProcessEmptyResponse(diags, response, errMessage, func() {})
+
+	return !diags.HasError()
+}
+
+func mergeRule(diags *diag.Diagnostics, errMessage string, rules []jsonRule, data RuleModel) ([]jsonRule, bool) {
+	rule, err := ruleToJson(ModelConditionalFeaturesRule{If: data.If, Then: data.Then})
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if rule, err = withManagedRuleMarker(rule, data.Priority.ValueInt64()); err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	return orderRules(append(removeManagedRule(rules, data.Priority.ValueInt64()), rule)), true
+}
+
+func removeManagedRule(rules []jsonRule, priority int64) []jsonRule {
+	if i := findManagedRule(rules, priority); i != -1 {
+		return append(rules[:i:i], rules[i+1:]...)
+	}
+
+	return rules
+}
+
+// setRuleSecrets removes the previous secrets of the rule and sets the new ones.
+func setRuleSecrets(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	secrets map[string]string,
+	previous map[string]attr.Value,
+	next types.Map,
+) bool {
+	for name := range previous {
+		delete(secrets, name)
+	}
+
+	if next.IsNull() {
+		return true
+	}
+
+	values, ok := util.StringMapToMap(ctx, diags, path.Root("secrets"), next)
+	if !ok {
+		return false
+	}
+
+	for name, value := range values {
+		secrets[name] = value
+	}
+
+	return true
+}
+
+func ruleId(cdnId int64, priority int64) string {
+	return fmt.Sprintf("%d/%d", cdnId, priority)
+}
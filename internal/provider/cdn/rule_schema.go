@@ -0,0 +1,59 @@
+package cdn
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RuleModel struct {
+	Id       types.String                     `tfsdk:"id"`
+	CdnId    types.Int64                      `tfsdk:"cdn_id"`
+	Priority types.Int64                      `tfsdk:"priority"`
+	If       *ModelConditionalFeaturesIf      `tfsdk:"if"`
+	Then     []ModelConditionalFeaturesAction `tfsdk:"then"`
+	Secrets  types.Map                        `tfsdk:"secrets"`
+}
+
+func CreateRuleResourceSchema() schema.Schema {
+	attrs := createConditionalFeaturesRuleSchemaAttrs()
+	attrs["id"] = schema.StringAttribute{
+		Computed:      true,
+		Description:   "ID of the rule in the format <cdn_id>/<priority>",
+		PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+	}
+	attrs["cdn_id"] = schema.Int64Attribute{
+		Required:      true,
+		Description:   "ID of the CDN Resource the rule belongs to",
+		PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+	}
+	attrs["priority"] = schema.Int64Attribute{
+		Required: true,
+		Description: "Priority of the rule; rules of the CDN are evaluated in ascending order of their priorities " +
+			"after the rules set by the CDN resource. The priority must be unique within the CDN.",
+		Validators:    []validator.Int64{int64validator.AtLeast(0)},
+		PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+	}
+	attrs["secrets"] = schema.MapAttribute{
+		Optional:    true,
+		Sensitive:   true,
+		ElementType: types.StringType,
+		Description: "Conditional feature secrets used by the rule; secret names must be unique within the CDN",
+	}
+
+	return schema.Schema{
+		Description: "CDN rule resource manages a single conditional feature rule of a CDN Resource, so different " +
+			"teams can own different rules of a shared CDN. All rules of a CDN are merged into its conditional " +
+			`features configuration. The rule is identified by an additional "set_var" action setting the variable "` +
+			managedRuleVariable + `" to the priority of the rule; the variable is visible to the conditions of ` +
+			`the following rules, but the marker is left out of the "conditional_features_lint" check and of the ` +
+			`"cdn77_conditional_features_evaluate" data source. No other action may set the variable, so the rules ` +
+			`aren't mistaken for each other. Set "conditional_features_ignore_external_rules" ` +
+			`of the "cdn77_cdn" resource to true, otherwise the CDN resource removes the rules on its next update.`,
+		Attributes: attrs,
+	}
+}
@@ -0,0 +1,310 @@
+package cdn_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestRuleResource_Secrets(t *testing.T) {
+	const configuration = `[{"if":[{"type":"path-prefix","prefix":"/a"}],"then":[` +
+		`{"name":"set_var","config":{"key":"a","value":"1"}},` +
+		`{"name":"set_var","config":{"key":"terraform_rule","value":"10"}}]}]`
+
+	ruleModel := func(value string, secrets types.Map) cdn.RuleModel {
+		return cdn.RuleModel{
+			Id:       types.StringValue("1/10"),
+			CdnId:    types.Int64Value(1),
+			Priority: types.Int64Value(10),
+			If: &cdn.ModelConditionalFeaturesIf{
+				Operator: types.StringNull(),
+				Conditions: []cdn.ModelConditionalFeaturesCondition{
+					{Type: types.StringValue("path-prefix"), Args: stringMap("prefix", "/a"), ListArgs: nullListArgs()},
+				},
+			},
+			Then: []cdn.ModelConditionalFeaturesAction{
+				{Name: types.StringValue("set_var"), Config: util.NewJsonValue(`{"key":"a","value":"` + value + `"}`)},
+			},
+			Secrets: secrets,
+		}
+	}
+
+	testCases := map[string]struct {
+		plan            cdn.RuleModel
+		expectedSecrets map[string]string
+	}{
+		"unchanged secrets aren't sent": {
+			plan: ruleModel("2", stringMap("rule_token", "old")),
+		},
+		"changed secrets are merged with the other secrets": {
+			plan:            ruleModel("1", stringMap("rule_token", "new")),
+			expectedSecrets: map[string]string{"cdn_token": "****", "rule_token": "new"},
+		},
+		"removed secrets are removed": {
+			plan:            ruleModel("1", types.MapNull(types.StringType)),
+			expectedSecrets: map[string]string{"cdn_token": "****"},
+		},
+	}
+
+	s := cdn.CreateRuleResourceSchema()
+	nullRaw := tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var rules []cdn77.ConditionalFeatureConfiguration
+			if err := json.Unmarshal([]byte(configuration), &rules); err != nil {
+				t.Fatal(err)
+			}
+
+			// The API doesn't return the values of the secrets.
+			secrets := map[string]string{"cdn_token": "****", "rule_token": "****"}
+			var edits []cdn77.CdnEditJSONRequestBody
+
+			r := &cdn.RuleResource{BaseResource: util.NewBaseResource("cdn_rule", cdn.CreateRuleResourceSchema, nil)}
+			r.Client = cdnClient{
+				cdn: &cdn77.Cdn{
+					ConditionalFeatures: &cdn77.ConditionalFeatures{Configuration: &rules, Secrets: &secrets},
+				},
+				edits: &edits,
+			}
+
+			state := tfsdk.State{Schema: s, Raw: nullRaw}
+			plan := tfsdk.Plan{Schema: s, Raw: nullRaw}
+			diags := state.Set(t.Context(), ruleModel("1", stringMap("rule_token", "old")))
+			diags.Append(plan.Set(t.Context(), tc.plan)...)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			resp := fwresource.UpdateResponse{State: state}
+			r.Update(t.Context(), fwresource.UpdateRequest{State: state, Plan: plan}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			if len(edits) != 1 {
+				t.Fatalf("expected 1 edit, got %d", len(edits))
+			}
+
+			actual := edits[0].ConditionalFeatures.Secrets
+			switch {
+			case tc.expectedSecrets == nil && actual != nil:
+				t.Errorf("expected no secrets to be sent, got %v", *actual)
+			case tc.expectedSecrets != nil && (actual == nil || !maps.Equal(*actual, tc.expectedSecrets)):
+				t.Errorf("expected secrets %v to be sent, got %v", tc.expectedSecrets, actual)
+			}
+
+			// Secrets in the state aren't overwritten by the values returned by the API.
+			readResp := fwresource.ReadResponse{State: resp.State}
+			r.Read(t.Context(), fwresource.ReadRequest{State: resp.State}, &readResp)
+
+			var data cdn.RuleModel
+			if diags := readResp.State.Get(t.Context(), &data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if !data.Secrets.Equal(tc.plan.Secrets) {
+				t.Errorf("expected secrets %s, got %s", tc.plan.Secrets, data.Secrets)
+			}
+		})
+	}
+}
+
+func TestManagedRuleVariableValidator(t *testing.T) {
+	const reserved = `the variable "terraform_rule" is reserved`
+
+	testCases := map[string]struct {
+		configuration string
+		expectedError string
+	}{
+		"other variable": {
+			configuration: `[{"then":[{"name":"set_var","config":{"key":"terraform","value":"1"}}]}]`,
+		},
+		"other action": {
+			configuration: `[{"then":[{"name":"response_hdr","config":{"key":"terraform_rule"}}]}]`,
+		},
+		"marker": {
+			configuration: `[{"then":[{"name":"cache_disable"},` +
+				`{"name":"set_var","config":{"key":"terraform_rule","value":"10"}}]}]`,
+			expectedError: `Element "/0/then/1": ` + reserved,
+		},
+		"reserved variable": {
+			configuration: `[{"then":[{"name":"set_var","config":{"key":"terraform_rule","value":"on"}}]}]`,
+			expectedError: `Element "/0/then/0": ` + reserved,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stringReq := validator.StringRequest{
+				Path:        path.Root("conditional_features").AtName("configuration"),
+				ConfigValue: types.StringValue(tc.configuration),
+			}
+			stringResp := validator.StringResponse{}
+
+			cdn.ManagedRuleVariableValidator{}.ValidateString(t.Context(), stringReq, &stringResp)
+
+			var rules []struct {
+				Then []struct {
+					Name   string          `json:"name"`
+					Config json.RawMessage `json:"config"`
+				} `json:"then"`
+			}
+			if err := json.Unmarshal([]byte(tc.configuration), &rules); err != nil {
+				t.Fatal(err)
+			}
+
+			objectResp := validator.ObjectResponse{}
+
+			for i, action := range rules[0].Then {
+				objectReq := validator.ObjectRequest{
+					Path: path.Root("then").AtListIndex(i),
+					ConfigValue: types.ObjectValueMust(
+						map[string]attr.Type{"name": types.StringType, "config": util.JsonType{}},
+						map[string]attr.Value{
+							"name":   types.StringValue(action.Name),
+							"config": util.NewJsonValue(string(action.Config)),
+						},
+					),
+				}
+
+				cdn.ManagedRuleVariableValidator{}.ValidateObject(t.Context(), objectReq, &objectResp)
+			}
+
+			if tc.expectedError == "" {
+				if stringResp.Diagnostics.HasError() || objectResp.Diagnostics.HasError() {
+					t.Errorf("unexpected errors: %v, %v", stringResp.Diagnostics, objectResp.Diagnostics)
+				}
+
+				return
+			}
+
+			stringErrs := stringResp.Diagnostics.Errors()
+			if len(stringErrs) != 1 || !strings.HasPrefix(stringErrs[0].Detail(), tc.expectedError) {
+				t.Errorf("expected an error starting with %q, got %v", tc.expectedError, stringErrs)
+			}
+
+			objectErrs := objectResp.Diagnostics.Errors()
+			if len(objectErrs) != 1 || !strings.HasPrefix(objectErrs[0].Detail(), reserved) {
+				t.Errorf("expected an error starting with %q, got %v", reserved, objectErrs)
+			}
+		})
+	}
+}
+
+func TestAccCdnRuleResource(t *testing.T) {
+	const rsc = "cdn77_cdn_rule.cors"
+	client := acctest.GetClient(t)
+	var cdnId string
+
+	config := func(label string, corsPrefix string) string {
+		return OriginResourceConfig + fmt.Sprintf(`
+resource "cdn77_cdn" "lorem" {
+  label     = %q
+  origin_id = cdn77_origin_url.url.id
+
+  conditional_features_ignore_external_rules = true
+
+  conditional_features = {
+    rule = [
+      {
+        if   = { conditions = [{ type = "path-prefix", args = { prefix = "/own" } }] }
+        then = [{ name = "set_var", config = jsonencode({ key = "owner", value = "cdn" }) }]
+      },
+    ]
+  }
+}
+
+resource "cdn77_cdn_rule" "no_cache" {
+  cdn_id   = cdn77_cdn.lorem.id
+  priority = 20
+
+  if   = { conditions = [{ type = "query-param", args = { key = "preview" } }] }
+  then = [{ name = "set_var", config = jsonencode({ key = "owner", value = "no_cache" }) }]
+}
+
+resource "cdn77_cdn_rule" "cors" {
+  cdn_id   = cdn77_cdn.lorem.id
+  priority = 10
+
+  if   = { conditions = [{ type = "path-prefix", args = { prefix = %q } }] }
+  then = [{ name = "set_var", config = jsonencode({ key = "owner", value = "cors" }) }]
+}
+`, label, corsPrefix)
+	}
+
+	acctest.Run(t, checkCdnsAndOriginDestroyed(client),
+		resource.TestStep{
+			Config: config("cdn with rule resources", "/api"),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr("cdn77_cdn.lorem", "id", &cdnId),
+				resource.TestCheckResourceAttr(rsc, "if.conditions.0.args.prefix", "/api"),
+				resource.TestCheckResourceAttr(rsc, "then.#", "1"),
+				resource.TestCheckResourceAttr("cdn77_cdn.lorem", "conditional_features.rule.#", "1"),
+				checkCdn(client, &cdnId, checkRuleOwners("cdn", "cors", "no_cache")),
+			),
+		},
+		resource.TestStep{
+			Config:           config("cdn with updated rule resources", "/v2/api"),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionUpdate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr(rsc, "if.conditions.0.args.prefix", "/v2/api"),
+				checkCdn(client, &cdnId, checkRuleOwners("cdn", "cors", "no_cache")),
+			),
+		},
+		resource.TestStep{
+			ResourceName:      rsc,
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	)
+}
+
+// checkRuleOwners checks the order of the rules using the "owner" variable set by their first action.
+func checkRuleOwners(owners ...string) func(c *cdn77.Cdn) error {
+	return func(c *cdn77.Cdn) error {
+		configuration := *c.ConditionalFeatures.Configuration
+		if err := acctest.EqualField("rule count", len(configuration), len(owners)); err != nil {
+			return err
+		}
+
+		for i, rule := range configuration {
+			raw, err := json.Marshal((*rule.Then)[0].Config)
+			if err != nil {
+				return err
+			}
+
+			var config struct {
+				Value string `json:"value"`
+			}
+
+			if err := json.Unmarshal(raw, &config); err != nil {
+				return err
+			}
+
+			if err := acctest.EqualField(fmt.Sprintf("rule #%d owner", i), config.Value, owners[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
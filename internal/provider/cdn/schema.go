@@ -1,11 +1,17 @@
 package cdn
 
 import (
+	"strings"
+
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
@@ -40,8 +46,48 @@ type Model struct {
 	QueryString               *ModelQueryString         `tfsdk:"query_string"`
 	RateLimitEnabled          types.Bool                `tfsdk:"rate_limit_enabled"`
 	SecureToken               *ModelSecureToken         `tfsdk:"secure_token"`
+	SecureTokenWo             types.String              `tfsdk:"secure_token_wo"`
+	SecureTokenWoVersion      types.Int64               `tfsdk:"secure_token_wo_version"`
 	Ssl                       *ModelSsl                 `tfsdk:"ssl"`
 	ConditionalFeatures       *ModelConditionalFeatures `tfsdk:"conditional_features"`
+	ConditionalFeaturesLint   types.String              `tfsdk:"conditional_features_lint"`
+	IgnoreExternalRules       types.Bool                `tfsdk:"conditional_features_ignore_external_rules"`
+	IgnoreExternalCnames      types.Bool                `tfsdk:"ignore_external_cnames"`
+	IgnoreSections            types.Set                 `tfsdk:"ignore_sections"`
+	OnCreateFailure           types.String              `tfsdk:"on_create_failure"`
+	DeletionProtection        types.Bool                `tfsdk:"deletion_protection"`
+}
+
+// DataSourceModel is the model of the CDN data sources; it leaves out the resource-only attributes (see
+// ResourceOnlyAttrs).
+type DataSourceModel struct {
+	Id                        types.Int64                         `tfsdk:"id"`
+	Label                     types.String                        `tfsdk:"label"`
+	OriginId                  types.String                        `tfsdk:"origin_id"`
+	CreationTime              types.String                        `tfsdk:"creation_time"`
+	Url                       types.String                        `tfsdk:"url"`
+	Stream                    *ModelStream                        `tfsdk:"stream"`
+	Cache                     *ModelCache                         `tfsdk:"cache"`
+	Cnames                    types.Set                           `tfsdk:"cnames"`
+	GeoProtection             *ModelGeoProtection                 `tfsdk:"geo_protection"`
+	Headers                   *ModelHeaders                       `tfsdk:"headers"`
+	HotlinkProtection         *ModelHotlinkProtection             `tfsdk:"hotlink_protection"`
+	HttpsRedirect             *ModelHttpsRedirect                 `tfsdk:"https_redirect"`
+	IpProtection              *ModelIpProtection                  `tfsdk:"ip_protection"`
+	Mp4PseudoStreamingEnabled types.Bool                          `tfsdk:"mp4_pseudo_streaming_enabled"`
+	Note                      types.String                        `tfsdk:"note"`
+	OriginHeaders             types.Map                           `tfsdk:"origin_headers"`
+	QueryString               *ModelQueryString                   `tfsdk:"query_string"`
+	RateLimitEnabled          types.Bool                          `tfsdk:"rate_limit_enabled"`
+	SecureToken               *ModelSecureToken                   `tfsdk:"secure_token"`
+	Ssl                       *ModelSsl                           `tfsdk:"ssl"`
+	ConditionalFeatures       *DataSourceModelConditionalFeatures `tfsdk:"conditional_features"`
+}
+
+type DataSourceModelConditionalFeatures struct {
+	Configuration util.JsonValue `tfsdk:"configuration"`
+	Rule          types.List     `tfsdk:"rule"`
+	Secrets       types.Map      `tfsdk:"secrets"`
 }
 
 type ModelStream struct {
@@ -99,17 +145,57 @@ type ModelSecureToken struct {
 }
 
 type ModelSsl struct {
-	Type  types.String `tfsdk:"type"`
-	SslId types.String `tfsdk:"ssl_id"`
+	Type          types.String `tfsdk:"type"`
+	SslId         types.String `tfsdk:"ssl_id"`
+	ResolvedSslId types.String `tfsdk:"resolved_ssl_id"`
 }
 
 type ModelConditionalFeatures struct {
-	Configuration types.String `tfsdk:"configuration"`
-	Secrets       types.Map    `tfsdk:"secrets"`
+	Configuration    util.JsonValue `tfsdk:"configuration"`
+	Rule             types.List     `tfsdk:"rule"`
+	Secrets          types.Map      `tfsdk:"secrets"`
+	SecretsWo        types.Map      `tfsdk:"secrets_wo"`
+	SecretsWoVersion types.Int64    `tfsdk:"secrets_wo_version"`
+}
+
+type ModelConditionalFeaturesRule struct {
+	If   *ModelConditionalFeaturesIf      `tfsdk:"if"`
+	Then []ModelConditionalFeaturesAction `tfsdk:"then"`
+}
+
+type ModelConditionalFeaturesIf struct {
+	Operator   types.String                        `tfsdk:"operator"`
+	Conditions []ModelConditionalFeaturesCondition `tfsdk:"conditions"`
+}
+
+type ModelConditionalFeaturesCondition struct {
+	Type     types.String `tfsdk:"type"`
+	Args     types.Map    `tfsdk:"args"`
+	ListArgs types.Map    `tfsdk:"list_args"`
+}
+
+type ModelConditionalFeaturesAction struct {
+	Name   types.String   `tfsdk:"name"`
+	Config util.JsonValue `tfsdk:"config"`
+}
+
+// ResourceOnlyAttrs are left out from the data source schemas; they only control how the resource is managed.
+var ResourceOnlyAttrs = []string{
+	"secure_token_wo",
+	"secure_token_wo_version",
+	"conditional_features.secrets_wo",
+	"conditional_features.secrets_wo_version",
+	"conditional_features_lint",
+	"conditional_features_ignore_external_rules",
+	"ignore_external_cnames",
+	"ignore_sections",
+	"on_create_failure",
+	"deletion_protection",
 }
 
 func CreateResourceSchema() schema.Schema {
 	return schema.Schema{
+		Version:     1,
 		Description: "CDN resource allows you to manage your CDNs",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -225,6 +311,11 @@ func CreateResourceSchema() schema.Schema {
 					"Otherwise it's not possible to generate an SSL certificate for any related CNAME.",
 				Default: setdefault.StaticValue(types.SetValueMust(types.StringType, nil)),
 			},
+			"ignore_external_cnames": schema.BoolAttribute{
+				Optional: true,
+				Description: `If true, CNAMEs that aren't set in "cnames" (e.g. the ones managed by ` +
+					`"cdn77_cdn_cname" resources) are neither read into "cnames" nor removed on update`,
+			},
 			"geo_protection": schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
 					"countries": schema.SetAttribute{
@@ -498,7 +589,10 @@ func CreateResourceSchema() schema.Schema {
 						Optional:    true,
 						Sensitive:   true,
 						Description: "Token length is between 8 and 64 characters.",
-						Validators:  []validator.String{stringvalidator.LengthBetween(8, 64)},
+						Validators: []validator.String{
+							stringvalidator.LengthBetween(8, 64),
+							stringvalidator.ConflictsWith(path.MatchRoot("secure_token_wo")),
+						},
 					},
 					"type": schema.StringAttribute{
 						Optional: true,
@@ -531,6 +625,21 @@ func CreateResourceSchema() schema.Schema {
 					},
 				)),
 			},
+			"secure_token_wo": schema.StringAttribute{
+				Optional:  true,
+				WriteOnly: true,
+				Description: `Write-only alternative to "secure_token.token" which is never stored in the state ` +
+					`(requires Terraform 1.11 or later). Change "secure_token_wo_version" to update the token.`,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(8, 64),
+					stringvalidator.AlsoRequires(path.MatchRoot("secure_token_wo_version")),
+				},
+			},
+			"secure_token_wo_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: `Version of "secure_token_wo"; change it whenever the write-only token changes`,
+				Validators:  []validator.Int64{int64validator.AlsoRequires(path.MatchRoot("secure_token_wo"))},
+			},
 			"ssl": schema.SingleNestedAttribute{
 				Attributes: map[string]schema.Attribute{
 					"type": schema.StringAttribute{
@@ -545,20 +654,29 @@ func CreateResourceSchema() schema.Schema {
 						Default: stringdefault.StaticString(string(cdn77.InstantSsl)),
 					},
 					"ssl_id": schema.StringAttribute{
-						Optional:    true,
-						Description: "ID (UUID) of the SSL certificate",
+						Optional: true,
+						Description: `ID (UUID) of the SSL certificate. With "` + autoSslId + `" (requires type ` +
+							`SNI), the non-expired SNI certificate covering all "cnames" with the latest expiration ` +
+							"is selected on every plan, so rotated certificates are applied automatically.",
+					},
+					"resolved_ssl_id": schema.StringAttribute{
+						Computed: true,
+						Description: `ID of the SSL certificate used by the CDN; it differs from "ssl_id" only ` +
+							`when "ssl_id" is "` + autoSslId + `"`,
 					},
 				},
 				Optional: true,
 				Computed: true,
 				Default: objectdefault.StaticValue(types.ObjectValueMust(
 					map[string]attr.Type{
-						"type":   basetypes.StringType{},
-						"ssl_id": basetypes.StringType{},
+						"type":            basetypes.StringType{},
+						"ssl_id":          basetypes.StringType{},
+						"resolved_ssl_id": basetypes.StringType{},
 					},
 					map[string]attr.Value{
-						"type":   types.StringValue(string(cdn77.InstantSsl)),
-						"ssl_id": types.StringNull(),
+						"type":            types.StringValue(string(cdn77.InstantSsl)),
+						"ssl_id":          types.StringNull(),
+						"resolved_ssl_id": types.StringNull(),
 					},
 				)),
 			},
@@ -567,18 +685,169 @@ func CreateResourceSchema() schema.Schema {
 				Description: "Conditional features configuration and secrets.",
 				Attributes: map[string]schema.Attribute{
 					"configuration": schema.StringAttribute{
-						Optional:      true,
-						Description:   "JSON configuration for conditional features.",
-						PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
-					},
+						CustomType: util.JsonType{},
+						Optional:   true,
+						Computed:   true,
+						Description: "JSON configuration for conditional features. " +
+							`Alternative to the attribute "rule" which is computed from this one and vice versa. ` +
+							"Whitespace and the order of object keys are ignored when comparing the values; " +
+							"the value read from the API is stored pretty printed. " +
+							`Actions must not set the variable "` + managedRuleVariable + `" which is reserved for ` +
+							`the rules managed by "cdn77_cdn_rule" resources.`,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("rule")),
+							ConditionalFeaturesConfigurationValidator{},
+							ManagedRuleVariableValidator{},
+						},
+						PlanModifiers: []planmodifier.String{ConfigurationAndRulePlanModifier{}},
+					},
+					"rule": createConditionalFeaturesRuleSchemaAttr(),
 					"secrets": schema.MapAttribute{
 						Optional:    true,
 						Computed:    true,
 						Sensitive:   true,
 						ElementType: types.StringType,
+						Validators: []validator.Map{
+							mapvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("secrets_wo")),
+						},
+					},
+					"secrets_wo": schema.MapAttribute{
+						Optional:    true,
+						WriteOnly:   true,
+						ElementType: types.StringType,
+						Description: `Write-only alternative to "secrets" which is never stored in the state ` +
+							`(requires Terraform 1.11 or later). Change "secrets_wo_version" to update the secrets.`,
+						Validators: []validator.Map{
+							mapvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("secrets_wo_version")),
+						},
+					},
+					"secrets_wo_version": schema.Int64Attribute{
+						Optional:    true,
+						Description: `Version of "secrets_wo"; change it whenever the write-only secrets change`,
+						Validators: []validator.Int64{
+							int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("secrets_wo")),
+						},
 					},
 				},
 			},
+			"conditional_features_lint": schema.StringAttribute{
+				Optional: true,
+				Description: "Severity of the issues found in conditional feature rules during planning " +
+					"(duplicate conditions, rules with the same conditions, conflicting actions, rules without " +
+					"effect and rules that can never match); one of " +
+					strings.Join(lintSeverities(), ", ") + ` (default "` + lintSeverityWarning + `")`,
+				Validators: []validator.String{stringvalidator.OneOf(lintSeverities()...)},
+			},
+			"ignore_sections": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Sections of the CDN managed outside of Terraform (e.g. in the CDN77 panel); one of " +
+					strings.Join(ignorableSections(), ", ") + ". The sections are never sent to the API and their " +
+					"values are only read from the API, so they must not be configured. New CDNs keep the default " +
+					"values of the sections in the state until the next refresh.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(ignorableSections()...)),
+				},
+			},
+			"conditional_features_ignore_external_rules": schema.BoolAttribute{
+				Optional: true,
+				Description: `If true, conditional feature rules managed by "cdn77_cdn_rule" resources are neither ` +
+					`read into "conditional_features" nor removed on update; the same applies to secrets that ` +
+					`aren't set in "conditional_features.secrets", which are sent only when "secrets" change. ` +
+					`The rules are recognized by their "set_var" marker action (see "cdn77_cdn_rule"). ` +
+					"Enable it whenever the CDN has such rules.",
+			},
+			"on_create_failure": schema.StringAttribute{
+				Optional: true,
+				Description: `What to do with a newly created CDN when applying its settings fails: "delete" ` +
+					`(default) removes it; "keep" saves it to the state, so Terraform marks it as tainted and the ` +
+					`CDN keeps its ID and URL (use "terraform untaint" to retry the edit instead of replacing the CDN)`,
+				Validators: []validator.String{stringvalidator.OneOf(onCreateFailureDelete, onCreateFailureKeep)},
+			},
+			"deletion_protection": util.DeletionProtectionSchemaAttr(),
+		},
+	}
+}
+
+func createConditionalFeaturesRuleSchemaAttr() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Optional: true,
+		Computed: true,
+		Description: "Conditional feature rules evaluated in the given order. Alternative to the attribute " +
+			`"configuration"; null if the configuration uses nested condition groups or condition arguments ` +
+			"which are neither strings nor lists of strings.",
+		NestedObject: schema.NestedAttributeObject{Attributes: createConditionalFeaturesRuleSchemaAttrs()},
+		Validators: []validator.List{
+			listvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("configuration")),
+		},
+		PlanModifiers: []planmodifier.List{ConfigurationAndRulePlanModifier{}},
+	}
+}
+
+// createConditionalFeaturesRuleSchemaAttrs returns the "if" and "then" attributes of a single rule.
+func createConditionalFeaturesRuleSchemaAttrs() map[string]schema.Attribute {
+	conditionAttrs := map[string]schema.Attribute{
+		"type": schema.StringAttribute{
+			Required:    true,
+			Description: `Type of the condition (e.g. "path-prefix" or "var")`,
+			Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+		},
+		"args": schema.MapAttribute{
+			Optional:    true,
+			ElementType: types.StringType,
+			Description: `String arguments of the condition (e.g. "prefix" or "key" and "value")`,
+			Validators:  []validator.Map{mapvalidator.SizeAtLeast(1)},
+		},
+		"list_args": schema.MapAttribute{
+			Optional:    true,
+			ElementType: types.ListType{ElemType: types.StringType},
+			Description: `Arguments of the condition which are lists of strings (e.g. "country" = ["CZ", "SK"]); ` +
+				`an argument can't be set in both "args" and "list_args"`,
+			Validators: []validator.Map{mapvalidator.SizeAtLeast(1)},
+		},
+	}
+	actionAttrs := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required:    true,
+			Description: `Name of the feature (e.g. "set_var" or "response_hdr")`,
+			Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+		},
+		"config": schema.StringAttribute{
+			Optional:    true,
+			CustomType:  util.JsonType{},
+			Description: "JSON object with the configuration of the feature; use jsonencode() to set it",
+			Validators:  []validator.String{jsonObjectValidator{}},
+		},
+	}
+
+	return map[string]schema.Attribute{
+		"if": schema.SingleNestedAttribute{
+			Optional:    true,
+			Description: "Conditions of the rule; the rule always applies if not set",
+			Attributes: map[string]schema.Attribute{
+				"operator": schema.StringAttribute{
+					Optional: true,
+					Description: "Operator combining the conditions; one of " +
+						strings.Join(conditionOperators(), ", ") + " (AND is used if not set)",
+					Validators: []validator.String{stringvalidator.OneOf(conditionOperators()...)},
+				},
+				"conditions": schema.ListNestedAttribute{
+					Required:     true,
+					Description:  "List of conditions",
+					Validators:   []validator.List{listvalidator.SizeAtLeast(1)},
+					NestedObject: schema.NestedAttributeObject{Attributes: conditionAttrs},
+				},
+			},
+		},
+		"then": schema.ListNestedAttribute{
+			Required: true,
+			Description: "Actions (features) applied when the conditions match; actions must not set the variable " +
+				`"` + managedRuleVariable + `" which is reserved for the rules managed by "cdn77_cdn_rule" resources`,
+			Validators: []validator.List{listvalidator.SizeAtLeast(1)},
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: actionAttrs,
+				Validators: []validator.Object{ManagedRuleVariableValidator{}},
+			},
 		},
 	}
 }
@@ -0,0 +1,104 @@
+package cdn_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+)
+
+func TestSignPath(t *testing.T) {
+	const token = "s3cr3t-token"
+	const expiresAt = 1700000000
+
+	testCases := []struct {
+		name      string
+		tokenType cdn77.SecureTokenType
+		path      string
+		expiresAt int64
+		clientIp  string
+		expected  string
+	}{
+		{
+			name:      "parameter",
+			tokenType: cdn77.SecureTokenTypeParameter,
+			path:      "/videos/movie.mp4",
+			expiresAt: expiresAt,
+			expected:  "/videos/movie.mp4?secure=c3ai73a9qEqYH013nW7ymA,1700000000",
+		},
+		{
+			name:      "parameter without expiration",
+			tokenType: cdn77.SecureTokenTypeParameter,
+			path:      "/videos/movie.mp4",
+			expected:  "/videos/movie.mp4?secure=75uJ_fPgZLVHWlnCfBh4xw",
+		},
+		{
+			name:      "parameter keeps query",
+			tokenType: cdn77.SecureTokenTypeParameter,
+			path:      "/videos/movie.mp4?a=b",
+			expiresAt: expiresAt,
+			expected:  "/videos/movie.mp4?a=b&secure=c3ai73a9qEqYH013nW7ymA,1700000000",
+		},
+		{
+			name:      "path",
+			tokenType: cdn77.SecureTokenTypePath,
+			path:      "/hls/stream/playlist.m3u8",
+			expiresAt: expiresAt,
+			expected:  "/4Sz9UlIuIE4woxKnF4tjiw,1700000000/hls/stream/playlist.m3u8",
+		},
+		{
+			name:      "highwinds",
+			tokenType: cdn77.SecureTokenTypeHighwinds,
+			path:      "/videos/movie.mp4",
+			expiresAt: expiresAt,
+			clientIp:  "192.0.2.1",
+			expected:  "/videos/movie.mp4?e=1700000000&ip=192.0.2.1&h=97d003dfefdc4fed83b2e304f993fab7",
+		},
+		{
+			name:      "highwinds keeps query",
+			tokenType: cdn77.SecureTokenTypeHighwinds,
+			path:      "/videos/movie.mp4?a=b",
+			expiresAt: expiresAt,
+			expected:  "/videos/movie.mp4?a=b&e=1700000000&h=3662aa85029d719ca654776a2e5db82f",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			signed, err := cdn.SignPath(tc.tokenType, token, tc.path, tc.expiresAt, tc.clientIp)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if signed != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, signed)
+			}
+		})
+	}
+}
+
+func TestSignPath_Errors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tokenType cdn77.SecureTokenType
+		token     string
+		path      string
+		clientIp  string
+		expected  error
+	}{
+		{"disabled", cdn77.SecureTokenTypeNone, "s3cr3t-token", "/file", "", cdn.ErrSecureTokenDisabled},
+		{"empty token", cdn77.SecureTokenTypePath, "", "/file", "", cdn.ErrSecureTokenEmpty},
+		{"relative path", cdn77.SecureTokenTypePath, "s3cr3t-token", "file", "", cdn.ErrSecureTokenPath},
+		{"client IP", cdn77.SecureTokenTypePath, "s3cr3t-token", "/file", "192.0.2.1", cdn.ErrSecureTokenClientIp},
+		{"unknown type", "unknown", "s3cr3t-token", "/file", "", cdn.ErrSecureTokenUnexpectedType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := cdn.SignPath(tc.tokenType, tc.token, tc.path, 0, tc.clientIp); !errors.Is(err, tc.expected) {
+				t.Errorf("expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cdn
+
+import (
+	"context"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &SignUrlFunction{}
+
+type SignUrlFunction struct{}
+
+func NewSignUrlFunction() function.Function {
+	return &SignUrlFunction{}
+}
+
+func (*SignUrlFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sign_url"
+}
+
+func (*SignUrlFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Signs a URL with a CDN secure token",
+		MarkdownDescription: "Signs an absolute URL the same way CDN77 verifies URLs protected by " +
+			"`cdn77_cdn.secure_token`. Supported token types are `parameter`, `path` and `highwinds`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "url",
+				MarkdownDescription: "Absolute URL to sign (e.g. `https://1234567890.rsc.cdn77.org/video.mp4`)",
+			},
+			function.StringParameter{
+				Name:                "type",
+				MarkdownDescription: "Secure token type; one of `parameter`, `path` or `highwinds`",
+			},
+			function.StringParameter{
+				Name:                "token",
+				MarkdownDescription: "Secure token of the CDN",
+			},
+			function.Int64Parameter{
+				Name:                "expires_at",
+				AllowNullValue:      true,
+				MarkdownDescription: "Unix timestamp when the URL expires; `null` creates a URL without expiration",
+			},
+			function.StringParameter{
+				Name:                "client_ip",
+				AllowNullValue:      true,
+				MarkdownDescription: "IP address the URL is restricted to (`highwinds` only); `null` to disable",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (*SignUrlFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rawUrl, tokenType, token string
+	var expiresAt types.Int64
+	var clientIp types.String
+
+	if resp.Error = req.Arguments.Get(ctx, &rawUrl, &tokenType, &token, &expiresAt, &clientIp); resp.Error != nil {
+		return
+	}
+
+	signed, err := SignUrl(
+		cdn77.SecureTokenType(tokenType),
+		token,
+		rawUrl,
+		expiresAt.ValueInt64(),
+		clientIp.ValueString(),
+	)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, signed)
+}
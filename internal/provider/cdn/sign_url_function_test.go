@@ -0,0 +1,101 @@
+package cdn_test
+
+import (
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSignUrlFunction(t *testing.T) {
+	testCases := []struct {
+		name          string
+		arguments     []attr.Value
+		expected      string
+		expectedError bool
+	}{
+		{
+			name: "parameter",
+			arguments: []attr.Value{
+				types.StringValue("https://cdn.example.com/videos/movie.mp4"),
+				types.StringValue("parameter"),
+				types.StringValue("s3cr3t-token"),
+				types.Int64Value(1700000000),
+				types.StringNull(),
+			},
+			expected: "https://cdn.example.com/videos/movie.mp4?secure=c3ai73a9qEqYH013nW7ymA,1700000000",
+		},
+		{
+			name: "path without expiration",
+			arguments: []attr.Value{
+				types.StringValue("http://cdn.example.com/videos/movie.mp4"),
+				types.StringValue("path"),
+				types.StringValue("s3cr3t-token"),
+				types.Int64Null(),
+				types.StringNull(),
+			},
+			expected: "http://cdn.example.com/DOb1gEvMq09jeu0FV8wsqA/videos/movie.mp4",
+		},
+		{
+			name: "highwinds with client IP",
+			arguments: []attr.Value{
+				types.StringValue("https://cdn.example.com/videos/movie.mp4"),
+				types.StringValue("highwinds"),
+				types.StringValue("s3cr3t-token"),
+				types.Int64Value(1700000000),
+				types.StringValue("192.0.2.1"),
+			},
+			expected: "https://cdn.example.com/videos/movie.mp4" +
+				"?e=1700000000&ip=192.0.2.1&h=97d003dfefdc4fed83b2e304f993fab7",
+		},
+		{
+			name: "relative URL",
+			arguments: []attr.Value{
+				types.StringValue("/videos/movie.mp4"),
+				types.StringValue("parameter"),
+				types.StringValue("s3cr3t-token"),
+				types.Int64Null(),
+				types.StringNull(),
+			},
+			expectedError: true,
+		},
+		{
+			name: "client IP with parameter type",
+			arguments: []attr.Value{
+				types.StringValue("https://cdn.example.com/videos/movie.mp4"),
+				types.StringValue("parameter"),
+				types.StringValue("s3cr3t-token"),
+				types.Int64Null(),
+				types.StringValue("192.0.2.1"),
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := function.RunRequest{Arguments: function.NewArgumentsData(tc.arguments)}
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+
+			cdn.NewSignUrlFunction().Run(t.Context(), req, &resp)
+
+			if tc.expectedError {
+				if resp.Error == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if result := resp.Result.Value(); !result.Equal(types.StringValue(tc.expected)) {
+				t.Errorf("expected %q, got %s", tc.expected, result)
+			}
+		})
+	}
+}
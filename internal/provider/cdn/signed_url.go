@@ -0,0 +1,206 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var absolutePathRegexp = regexp.MustCompile(`^/`)
+
+type SignedUrlModel struct {
+	CdnId     types.Int64  `tfsdk:"cdn_id"`
+	Path      types.String `tfsdk:"path"`
+	Host      types.String `tfsdk:"host"`
+	Scheme    types.String `tfsdk:"scheme"`
+	ValidFor  types.String `tfsdk:"valid_for"`
+	ExpiresAt types.Int64  `tfsdk:"expires_at"`
+	ClientIp  types.String `tfsdk:"client_ip"`
+	Url       types.String `tfsdk:"url"`
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &SignedUrlEphemeralResource{}
+
+type SignedUrlEphemeralResource struct {
+	client cdn77.ClientWithResponsesInterface
+}
+
+func NewSignedUrlEphemeralResource() ephemeral.EphemeralResource {
+	return &SignedUrlEphemeralResource{}
+}
+
+func (*SignedUrlEphemeralResource) Metadata(
+	_ context.Context,
+	req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "signed_url"}, "_")
+}
+
+func (*SignedUrlEphemeralResource) Schema(
+	_ context.Context,
+	_ ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse,
+) {
+	expiryValidator := stringvalidator.ConflictsWith(path.MatchRoot("expires_at"))
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cdn_id": schema.Int64Attribute{
+				Description: "ID of the CDN whose secure token is used for signing",
+				Required:    true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the file (including the leading slash and optionally a query string)",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.RegexMatches(absolutePathRegexp, "must start with /")},
+			},
+			"host": schema.StringAttribute{
+				Description: "Host of the signed URL; defaults to the CDN URL (use it to sign URLs for a CNAME)",
+				Optional:    true,
+				Computed:    true,
+			},
+			"scheme": schema.StringAttribute{
+				Description: `Scheme of the signed URL; either "http" or "https" (default)`,
+				Optional:    true,
+				Computed:    true,
+				Validators:  []validator.String{stringvalidator.OneOf("http", "https")},
+			},
+			"valid_for": schema.StringAttribute{
+				Description: `How long the URL stays valid from now, as a Go duration (e.g. "30m" or "12h")`,
+				Optional:    true,
+				Validators:  []validator.String{durationValidator{}, expiryValidator},
+			},
+			"expires_at": schema.Int64Attribute{
+				Description: "Unix timestamp when the URL expires; the URL doesn't expire if neither this attribute " +
+					`nor "valid_for" is set`,
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"client_ip": schema.StringAttribute{
+				Description: "IP address the URL is restricted to; supported only by the highwinds secure token type",
+				Optional:    true,
+			},
+			"url": schema.StringAttribute{
+				Description: "The signed URL",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+		MarkdownDescription: "Signed URL ephemeral resource generates a URL protected by the secure token " +
+			"of a CDN without storing it in the state",
+	}
+}
+
+func (r *SignedUrlEphemeralResource) Configure(
+	_ context.Context,
+	req ephemeral.ConfigureRequest,
+	resp *ephemeral.ConfigureResponse,
+) {
+	resp.Diagnostics.Append(util.MaybeSetClient(req.ProviderData, &r.client))
+}
+
+func (r *SignedUrlEphemeralResource) Open(
+	ctx context.Context,
+	req ephemeral.OpenRequest,
+	resp *ephemeral.OpenResponse,
+) {
+	const errMessage = "Failed to sign URL"
+
+	var data SignedUrlModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	cdnId := data.CdnId.ValueInt64()
+
+	response, err := r.client.CdnDetailWithResponse(ctx, int(cdnId))
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(detail *cdn77.Cdn) {
+		if detail.SecureToken.Token == nil {
+			diags.AddError(errMessage, fmt.Sprintf("CDN with id=%d doesn't have a secure token", cdnId))
+
+			return
+		}
+
+		if !data.ValidFor.IsNull() {
+			validFor, _ := time.ParseDuration(data.ValidFor.ValueString())
+			data.ExpiresAt = types.Int64Value(time.Now().Add(validFor).Unix())
+		}
+
+		if data.Host.IsNull() {
+			data.Host = types.StringValue(detail.Url)
+		}
+
+		if data.Scheme.IsNull() {
+			data.Scheme = types.StringValue("https")
+		}
+
+		signedUrl, err := SignUrl(
+			detail.SecureToken.Type,
+			*detail.SecureToken.Token,
+			data.Scheme.ValueString()+"://"+data.Host.ValueString()+data.Path.ValueString(),
+			data.ExpiresAt.ValueInt64(),
+			data.ClientIp.ValueString(),
+		)
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
+
+			return
+		}
+
+		data.Url = types.StringValue(signedUrl)
+
+		diags.Append(resp.Result.Set(ctx, data)...)
+	})
+}
+
+type durationValidator struct{}
+
+func (durationValidator) Description(context.Context) string {
+	return "value must be a valid duration"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (durationValidator) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration", err.Error())
+
+		return
+	}
+
+	if d <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration", "Duration must be positive")
+	}
+}
@@ -0,0 +1,239 @@
+package cdn
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type UsageCdnModel struct {
+	Id     types.Int64  `tfsdk:"id"`
+	Label  types.String `tfsdk:"label"`
+	Cnames types.Set    `tfsdk:"cnames"`
+}
+
+var (
+	_ datasource.DataSourceWithConfigure = &OriginUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &SslUsageDataSource{}
+)
+
+type OriginUsageDataSource struct {
+	client cdn77.ClientWithResponsesInterface
+}
+
+func NewOriginUsageDataSource() datasource.DataSource {
+	return &OriginUsageDataSource{}
+}
+
+func (*OriginUsageDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "origin_usage"}, "_")
+}
+
+func (*OriginUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = createUsageSchema(
+		"origin_id",
+		"ID of the Origin",
+		"Origin usage data source lists the CDNs which use the given Origin",
+	)
+}
+
+func (d *OriginUsageDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	resp.Diagnostics.Append(util.MaybeSetClient(req.ProviderData, &d.client))
+}
+
+func (d *OriginUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	const errMessage = "Failed to fetch CDNs using the Origin"
+
+	diags := &resp.Diagnostics
+	var originId types.String
+
+	if diags.Append(req.Config.GetAttribute(ctx, path.Root("origin_id"), &originId)...); diags.HasError() {
+		return
+	}
+
+	response, err := d.client.CdnListWithResponse(ctx)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(summaries *[]cdn77.CdnSummary) {
+		cdns := []UsageCdnModel{}
+
+		for _, summary := range *summaries {
+			if util.NullableToStringValue(summary.OriginId).ValueString() == originId.ValueString() {
+				cdns = append(cdns, newUsageCdnModel(ctx, diags, summary.Id, summary.Label, summary.Cnames))
+			}
+		}
+
+		setUsageState(ctx, diags, resp, "origin_id", originId, cdns)
+	})
+}
+
+type SslUsageDataSource struct {
+	client cdn77.ClientWithResponsesInterface
+}
+
+func NewSslUsageDataSource() datasource.DataSource {
+	return &SslUsageDataSource{}
+}
+
+func (*SslUsageDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "ssl_usage"}, "_")
+}
+
+func (*SslUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = createUsageSchema(
+		"ssl_id",
+		"ID of the SSL certificate",
+		"SSL usage data source lists the CDNs which serve the given SSL certificate",
+	)
+}
+
+func (d *SslUsageDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	resp.Diagnostics.Append(util.MaybeSetClient(req.ProviderData, &d.client))
+}
+
+func (d *SslUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	const errMessage = "Failed to fetch CDNs using the SSL certificate"
+
+	diags := &resp.Diagnostics
+	var sslId types.String
+
+	if diags.Append(req.Config.GetAttribute(ctx, path.Root("ssl_id"), &sslId)...); diags.HasError() {
+		return
+	}
+
+	sslResponse, err := d.client.SslSniDetailWithResponse(ctx, sslId.ValueString())
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	assignedIds := map[int]struct{}{}
+
+	util.ProcessResponse(diags, sslResponse, errMessage, sslResponse.JSON200, func(ssl *cdn77.Ssl) {
+		for _, assigned := range ssl.AssignedResources {
+			assignedIds[assigned.Id] = struct{}{}
+		}
+	})
+
+	if diags.HasError() {
+		return
+	}
+
+	// The SSL only lists IDs and labels of its CDNs, so their CNAMEs are taken from the list of CDNs.
+	response, err := d.client.CdnListWithResponse(ctx)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(summaries *[]cdn77.CdnSummary) {
+		cdns := []UsageCdnModel{}
+
+		for _, summary := range *summaries {
+			if _, ok := assignedIds[summary.Id]; ok {
+				cdns = append(cdns, newUsageCdnModel(ctx, diags, summary.Id, summary.Label, summary.Cnames))
+			}
+		}
+
+		setUsageState(ctx, diags, resp, "ssl_id", sslId, cdns)
+	})
+}
+
+func createUsageSchema(idAttr string, idDescription string, description string) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			idAttr: schema.StringAttribute{
+				Required:    true,
+				Description: idDescription,
+			},
+			"cdns": schema.ListNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "ID of the CDN",
+						},
+						"label": schema.StringAttribute{
+							Computed:    true,
+							Description: "Label of the CDN",
+						},
+						"cnames": schema.SetAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "CNAMEs of the CDN",
+						},
+					},
+				},
+				Computed:    true,
+				Description: "List of the CDNs ordered by their IDs",
+			},
+		},
+		Description: description,
+	}
+}
+
+func newUsageCdnModel(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	id int,
+	label string,
+	cnames cdn77.Cnames,
+) UsageCdnModel {
+	names := make([]string, 0, len(cnames))
+	for _, c := range cnames {
+		names = append(names, c.Cname)
+	}
+
+	return UsageCdnModel{
+		Id:     types.Int64Value(int64(id)),
+		Label:  types.StringValue(label),
+		Cnames: util.SetValueFrom(ctx, diags, types.StringType, names),
+	}
+}
+
+func setUsageState(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	resp *datasource.ReadResponse,
+	idAttr string,
+	id types.String,
+	cdns []UsageCdnModel,
+) {
+	slices.SortStableFunc(cdns, func(a, b UsageCdnModel) int {
+		return cmp.Compare(a.Id.ValueInt64(), b.Id.ValueInt64())
+	})
+
+	diags.Append(resp.State.SetAttribute(ctx, path.Root(idAttr), id)...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("cdns"), cdns)...)
+}
@@ -0,0 +1,120 @@
+package cdn_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest/testdata"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOriginUsageDataSource(t *testing.T) {
+	const rsc = "data.cdn77_origin_usage.usage"
+	client := acctest.GetClient(t)
+
+	originRequest := cdn77.OriginCreateUrlJSONRequestBody{
+		Label:  "random origin",
+		Scheme: "https",
+		Host:   "my-totally-random-custom-host.com",
+	}
+	originResponse, err := client.OriginCreateUrlWithResponse(t.Context(), originRequest)
+	acctest.AssertResponseOk(t, "Failed to create Origin: %s", originResponse, err)
+
+	originId := originResponse.JSON201.Id
+
+	t.Cleanup(func() {
+		acctest.MustDeleteOrigin(t, client, origin.TypeUrl, originId)
+	})
+
+	cdnRequest := cdn77.CdnAddJSONRequestBody{
+		Label:    "some cdn",
+		OriginId: originId,
+		Cnames:   util.Pointer([]string{"my.cdn.cz"}),
+	}
+	cdnResponse, err := client.CdnAddWithResponse(t.Context(), cdnRequest)
+	acctest.AssertResponseOk(t, "Failed to create CDN: %s", cdnResponse, err)
+
+	cdnId := cdnResponse.JSON201.Id
+
+	t.Cleanup(func() {
+		acctest.MustDeleteCdn(t, client, cdnId)
+	})
+
+	acctest.Run(t, nil,
+		resource.TestStep{
+			Config: fmt.Sprintf(`data "cdn77_origin_usage" "usage" {
+				origin_id = %q
+			}`, originId),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr(rsc, "origin_id", originId),
+				resource.TestCheckResourceAttr(rsc, "cdns.#", "1"),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.id", strconv.Itoa(cdnId)),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.label", "some cdn"),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.cnames.#", "1"),
+				resource.TestCheckTypeSetElemAttr(rsc, "cdns.0.cnames.*", "my.cdn.cz"),
+			),
+		},
+	)
+}
+
+func TestAccSslUsageDataSource(t *testing.T) {
+	const rsc = "data.cdn77_ssl_usage.usage"
+	client := acctest.GetClient(t)
+	sslId := acctest.MustAddSslWithCleanup(t, client, testdata.SslCert1, testdata.SslKey)
+
+	originRequest := cdn77.OriginCreateUrlJSONRequestBody{
+		Label:  "random origin",
+		Scheme: "https",
+		Host:   "my-totally-random-custom-host.com",
+	}
+	originResponse, err := client.OriginCreateUrlWithResponse(t.Context(), originRequest)
+	acctest.AssertResponseOk(t, "Failed to create Origin: %s", originResponse, err)
+
+	originId := originResponse.JSON201.Id
+
+	t.Cleanup(func() {
+		acctest.MustDeleteOrigin(t, client, origin.TypeUrl, originId)
+	})
+
+	addCdn := func(label string, cnames []string) int {
+		cdnRequest := cdn77.CdnAddJSONRequestBody{Label: label, OriginId: originId, Cnames: &cnames}
+		cdnResponse, err := client.CdnAddWithResponse(t.Context(), cdnRequest)
+		acctest.AssertResponseOk(t, "Failed to create CDN: %s", cdnResponse, err)
+
+		cdnId := cdnResponse.JSON201.Id
+
+		t.Cleanup(func() {
+			acctest.MustDeleteCdn(t, client, cdnId)
+		})
+
+		return cdnId
+	}
+
+	cdnId := addCdn("some cdn", []string{"my.cdn.cz"})
+	addCdn("other cdn", []string{"other.cdn.cz"})
+
+	editRequest := cdn77.CdnEditJSONRequestBody{Ssl: &cdn77.CdnSsl{Type: cdn77.SNI, SslId: &sslId}}
+	editResponse, err := client.CdnEditWithResponse(t.Context(), cdnId, editRequest)
+	acctest.AssertResponseOk(t, "Failed to edit CDN: %s", editResponse, err)
+
+	acctest.Run(t, nil,
+		resource.TestStep{
+			Config: fmt.Sprintf(`data "cdn77_ssl_usage" "usage" {
+				ssl_id = %q
+			}`, sslId),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr(rsc, "ssl_id", sslId),
+				resource.TestCheckResourceAttr(rsc, "cdns.#", "1"),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.id", strconv.Itoa(cdnId)),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.label", "some cdn"),
+				resource.TestCheckResourceAttr(rsc, "cdns.0.cnames.#", "1"),
+				resource.TestCheckTypeSetElemAttr(rsc, "cdns.0.cnames.*", "my.cdn.cz"),
+			),
+		},
+	)
+}
@@ -0,0 +1,76 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/oapi-codegen/nullable"
+)
+
+// findCreatedOrigin looks for the Origin created by a request whose response was lost; the result is nil if there is
+// no such Origin.
+func findCreatedOrigin[T any](
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	marker util.IdempotencyMarker,
+	idAndNote func(origin T) (string, nullable.Nullable[string]),
+) *T {
+	const errMessage = "Failed to look for Origin created by a lost request"
+
+	response, err := client.OriginListWithResponse(ctx)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil
+	}
+
+	var found *T
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *cdn77.OriginList) {
+		var origins []T
+
+		for _, item := range *list {
+			if origin, err := item.ValueByDiscriminator(); err == nil {
+				if o, ok := origin.(T); ok {
+					origins = append(origins, o)
+				}
+			}
+		}
+
+		found = util.FindMarked(diags, "Origin", marker, origins, idAndNote)
+	})
+
+	return found
+}
+
+// removeIdempotencyMarker sets the note of a new Origin back to the configured one. It's only a cleanup (the reader
+// leaves the marker out), so a failure is reported as a warning.
+func removeIdempotencyMarker(
+	diags *diag.Diagnostics,
+	data SharedModel,
+	edit func(note nullable.Nullable[string]) (util.Response, error),
+) {
+	const errMessage = "Failed to remove idempotency marker from the note of Origin"
+
+	var editDiags diag.Diagnostics
+
+	response, err := edit(util.StringValueToNullable(data.Note))
+	if err != nil {
+		editDiags.AddError(errMessage, err.Error())
+	} else {
+		util.ProcessEmptyResponse(&editDiags, response, errMessage, func() {})
+	}
+
+	if editDiags.HasError() {
+		detail := fmt.Sprintf(
+			"Origin %s was created, but its note still contains the marker; the provider ignores it.\n\n%s",
+			data.Id.ValueString(),
+			editDiags[0].Detail(),
+		)
+		diags.AddWarning(errMessage, detail)
+	}
+}
@@ -0,0 +1,178 @@
+package origin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/oapi-codegen/nullable"
+)
+
+func TestUrlResource_CreateRemovesIdempotencyMarker(t *testing.T) {
+	testCases := map[string]struct {
+		note             types.String
+		lostResponse     bool
+		failEdit         bool
+		expectedNote     nullable.Nullable[string]
+		expectedWarnings int
+	}{
+		"created": {
+			note:         types.StringValue("some note"),
+			expectedNote: nullable.NewNullableWithValue("some note"),
+		},
+		"created without note": {
+			note:         types.StringNull(),
+			expectedNote: nullable.NewNullNullable[string](),
+		},
+		"adopted after lost response": {
+			note:             types.StringValue("some note"),
+			lostResponse:     true,
+			expectedNote:     nullable.NewNullableWithValue("some note"),
+			expectedWarnings: 1,
+		},
+		"failed edit": {
+			note:             types.StringValue("some note"),
+			failEdit:         true,
+			expectedWarnings: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := &createClient{lostResponse: tc.lostResponse, failEdit: tc.failEdit}
+			baseResource := util.NewBaseResource("origin_url", origin.CreateUrlResourceSchema, nil)
+			baseResource.Client = client
+			r := &origin.UrlResource{BaseResource: baseResource}
+
+			s := origin.CreateUrlResourceSchema()
+			plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+			diags := plan.Set(t.Context(), origin.UrlModel{UrlBaseModel: origin.UrlBaseModel{
+				SharedModel: origin.SharedModel{Id: types.StringUnknown(), Label: types.StringValue("label"), Note: tc.note},
+				UrlModel: shared.NewUrlModel(
+					t.Context(),
+					"https",
+					"example.com",
+					nullable.NewNullNullable[int](),
+					nullable.NewNullNullable[string](),
+				),
+			}})
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: s}}
+			r.Create(t.Context(), resource.CreateRequest{Plan: plan}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			if warnings := resp.Diagnostics.WarningsCount(); warnings != tc.expectedWarnings {
+				t.Errorf("expected %d warnings, got %v", tc.expectedWarnings, resp.Diagnostics)
+			}
+
+			if !tc.failEdit && !notesEqual(client.note, tc.expectedNote) {
+				t.Errorf("expected the note %v, got %v", tc.expectedNote, client.note)
+			}
+
+			var data origin.UrlModel
+			if diags := resp.State.Get(t.Context(), &data); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if data.Id.ValueString() != "origin-id" || !data.Note.Equal(tc.note) {
+				t.Errorf("expected Origin origin-id with the note %s, got %s with %s", tc.note, data.Id, data.Note)
+			}
+		})
+	}
+}
+
+func notesEqual(a nullable.Nullable[string], b nullable.Nullable[string]) bool {
+	if a.IsNull() || b.IsNull() {
+		return a.IsNull() == b.IsNull()
+	}
+
+	return a.MustGet() == b.MustGet()
+}
+
+// createClient creates a URL Origin and keeps its note up to date; if lostResponse is set, the creation responds
+// with 502 and the Origin is listed instead. If failEdit is set, the edits of the Origin fail.
+type createClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	lostResponse bool
+	failEdit     bool
+	note         nullable.Nullable[string]
+}
+
+func (c *createClient) OriginCreateUrlWithResponse(
+	_ context.Context,
+	body cdn77.OriginCreateUrlJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.OriginCreateUrlResponse, error) {
+	c.note = body.Note
+
+	if c.lostResponse {
+		return &cdn77.OriginCreateUrlResponse{HTTPResponse: &http.Response{StatusCode: http.StatusBadGateway}}, nil
+	}
+
+	return &cdn77.OriginCreateUrlResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusCreated},
+		JSON201:      c.detail("origin-id", c.note),
+	}, nil
+}
+
+func (c *createClient) OriginListWithResponse(
+	context.Context,
+	...cdn77.RequestEditorFn,
+) (*cdn77.OriginListResponse, error) {
+	list := make(cdn77.OriginList, 2)
+
+	other := c.detail("other-origin-id", nullable.NewNullableWithValue("other note"))
+	if err := list[0].FromUrlOriginDetail(*other); err != nil {
+		return nil, err
+	}
+
+	if err := list[1].FromUrlOriginDetail(*c.detail("origin-id", c.note)); err != nil {
+		return nil, err
+	}
+
+	return &cdn77.OriginListResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &list}, nil
+}
+
+func (c *createClient) OriginEditUrlWithResponse(
+	_ context.Context,
+	_ string,
+	body cdn77.OriginEditUrlJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.OriginEditUrlResponse, error) {
+	if c.failEdit {
+		return &cdn77.OriginEditUrlResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusUnprocessableEntity},
+			JSON422:      &cdn77.FieldErrors{Errors: []string{"invalid note"}},
+		}, nil
+	}
+
+	c.note = body.Note
+
+	return &cdn77.OriginEditUrlResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+func (*createClient) detail(id string, note nullable.Nullable[string]) *cdn77.UrlOriginDetail {
+	return &cdn77.UrlOriginDetail{
+		Id:     id,
+		Label:  "some label",
+		Note:   note,
+		Scheme: cdn77.OriginScheme("https"),
+		Host:   "example.com",
+	}
+}
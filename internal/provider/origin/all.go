@@ -19,7 +19,8 @@ import (
 type AllModel struct {
 	Aws           []AwsBaseModel           `tfsdk:"aws"`
 	ObjectStorage []ObjectStorageBaseModel `tfsdk:"object_storage"`
-	Url           []UrlModel               `tfsdk:"url"`
+	Storage       []StorageModel           `tfsdk:"storage"`
+	Url           []UrlBaseModel           `tfsdk:"url"`
 }
 
 var _ datasource.DataSourceWithConfigure = &AllDataSource{}
@@ -40,7 +41,8 @@ func (*AllDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp
 	converter := util.NewResourceDataSourceSchemaConverter()
 	awsAttrs := converter.Convert(CreateAwsBaseResourceSchema()).Attributes
 	objectStorageAttrs := converter.Convert(CreateObjectStorageBaseResourceSchema()).Attributes
-	urlAttrs := converter.Convert(CreateUrlResourceSchema()).Attributes
+	storageAttrs := converter.Convert(CreateStorageResourceSchema()).Attributes
+	urlAttrs := converter.Convert(CreateUrlBaseResourceSchema()).Attributes
 
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
@@ -54,6 +56,11 @@ func (*AllDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp
 				Computed:     true,
 				Description:  "List of all Object Storage Origins",
 			},
+			"storage": schema.ListNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{Attributes: storageAttrs},
+				Computed:     true,
+				Description:  "List of all CDN77 Storage Origins",
+			},
 			"url": schema.ListNestedAttribute{
 				NestedObject: schema.NestedAttributeObject{Attributes: urlAttrs},
 				Computed:     true,
@@ -85,7 +92,12 @@ func (d *AllDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp
 	}
 
 	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *cdn77.OriginList) {
-		data := AllModel{Aws: []AwsBaseModel{}, ObjectStorage: []ObjectStorageBaseModel{}, Url: []UrlModel{}}
+		data := AllModel{
+			Aws:           []AwsBaseModel{},
+			ObjectStorage: []ObjectStorageBaseModel{},
+			Storage:       []StorageModel{},
+			Url:           []UrlBaseModel{},
+		}
 
 		for _, item := range *list {
 			origin, err := item.ValueByDiscriminator()
@@ -114,8 +126,10 @@ func (d *AllDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp
 						SizeBytes: util.IntPointerToInt64Value(o.Usage.SizeBytes),
 					},
 				})
+			case cdn77.StorageOriginDetail:
+				data.Storage = append(data.Storage, NewStorageModel(types.StringValue(o.Id), &o))
 			case cdn77.UrlOriginDetail:
-				data.Url = append(data.Url, UrlModel{
+				data.Url = append(data.Url, UrlBaseModel{
 					SharedModel: NewSharedModel(types.StringValue(o.Id), o.Label, o.Note),
 					UrlModel:    shared.NewUrlModel(ctx, string(o.Scheme), o.Host, o.Port, o.BaseDir),
 				})
@@ -132,7 +146,10 @@ func (d *AllDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp
 		slices.SortStableFunc(data.ObjectStorage, func(a, b ObjectStorageBaseModel) int {
 			return cmp.Compare(a.Id.ValueString(), b.Id.ValueString())
 		})
-		slices.SortStableFunc(data.Url, func(a, b UrlModel) int {
+		slices.SortStableFunc(data.Storage, func(a, b StorageModel) int {
+			return cmp.Compare(a.Id.ValueString(), b.Id.ValueString())
+		})
+		slices.SortStableFunc(data.Url, func(a, b UrlBaseModel) int {
 			return cmp.Compare(a.Id.ValueString(), b.Id.ValueString())
 		})
 		diags.Append(resp.State.Set(ctx, data)...)
@@ -153,6 +153,7 @@ func TestAccOrigin_AllDataSource_Empty(t *testing.T) {
 		Check: resource.ComposeAggregateTestCheckFunc(
 			resource.TestCheckResourceAttr("data.cdn77_origins.all", "aws.#", "0"),
 			resource.TestCheckResourceAttr("data.cdn77_origins.all", "object_storage.#", "0"),
+			resource.TestCheckResourceAttr("data.cdn77_origins.all", "storage.#", "0"),
 			resource.TestCheckResourceAttr("data.cdn77_origins.all", "url.#", "0"),
 		),
 	})
@@ -9,9 +9,12 @@ import (
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
 	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oapi-codegen/nullable"
 )
 
 var (
@@ -32,33 +35,74 @@ func (r *AwsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	accessKeySecret, ok := getAccessKeySecret(ctx, diags, req.Config, data)
+	if !ok {
+		return
+	}
+
 	const errMessage = "Failed to create AWS Origin"
 
+	marker, err := util.NewIdempotencyMarker()
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
 	scheme, host, port, basePath := data.UrlModel.Parts(ctx)
 	request := cdn77.OriginCreateAwsJSONRequestBody{
 		Label:              data.Label.ValueString(),
-		Note:               util.StringValueToNullable(data.Note),
+		Note:               marker.Mark(util.StringValueToNullable(data.Note)),
 		Scheme:             cdn77.OriginScheme(scheme),
 		Host:               host,
 		Port:               port,
 		BaseDir:            basePath,
 		AwsAccessKeyId:     util.StringValueToNullable(data.AccessKeyId),
-		AwsAccessKeySecret: util.StringValueToNullable(data.AccessKeySecret),
+		AwsAccessKeySecret: util.StringValueToNullable(accessKeySecret),
 		AwsRegion:          util.StringValueToNullable(data.Region),
 	}
 
 	response, err := r.Client.OriginCreateAwsWithResponse(ctx, request)
-	if err != nil {
-		diags.AddError(errMessage, err.Error())
 
-		return
+	var detail *cdn77.S3OriginDetail
+
+	if util.IsLostResponse(response, err) {
+		var cancel context.CancelFunc
+		ctx, cancel = util.LostResponseContext(ctx)
+		defer cancel()
+
+		idAndNote := func(o cdn77.S3OriginDetail) (string, nullable.Nullable[string]) { return o.Id, o.Note }
+		if detail = findCreatedOrigin(ctx, r.Client, diags, marker, idAndNote); diags.HasError() {
+			return
+		}
 	}
 
-	util.ProcessResponse(diags, response, errMessage, response.JSON201, func(detail *cdn77.S3OriginDetail) {
-		data.Id = types.StringValue(detail.Id)
+	if detail == nil {
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
 
-		diags.Append(resp.State.Set(ctx, data)...)
-	})
+			return
+		}
+
+		util.ProcessResponse(diags, response, errMessage, response.JSON201, func(d *cdn77.S3OriginDetail) {
+			detail = d
+		})
+
+		if diags.HasError() {
+			return
+		}
+	}
+
+	data.Id = types.StringValue(detail.Id)
+
+	editNote := func(note nullable.Nullable[string]) (util.Response, error) {
+		editRequest := cdn77.OriginEditAwsJSONRequestBody{Note: note}
+
+		return r.Client.OriginEditAwsWithResponse(ctx, data.Id.ValueString(), editRequest)
+	}
+	removeIdempotencyMarker(diags, data.SharedModel, editNote)
+
+	diags.Append(resp.State.Set(ctx, data)...)
 }
 
 func (r *AwsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -69,6 +113,11 @@ func (r *AwsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
+	accessKeySecret, ok := getAccessKeySecret(ctx, diags, req.Config, data)
+	if !ok {
+		return
+	}
+
 	const errMessage = "Failed to update AWS Origin"
 
 	scheme, host, port, basePath := data.UrlModel.Parts(ctx)
@@ -80,7 +129,7 @@ func (r *AwsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		Port:               port,
 		BaseDir:            basePath,
 		AwsAccessKeyId:     util.StringValueToNullable(data.AccessKeyId),
-		AwsAccessKeySecret: util.StringValueToNullable(data.AccessKeySecret),
+		AwsAccessKeySecret: util.StringValueToNullable(accessKeySecret),
 		AwsRegion:          util.StringValueToNullable(data.Region),
 	}
 
@@ -106,6 +155,14 @@ func (r *AwsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	const errMessage = "Failed to delete AWS Origin"
 
+	if util.IsDeletionProtected(diags, data.DeletionProtection, errMessage) {
+		return
+	}
+
+	if !detachCdns(ctx, r.Client, diags, errMessage, data.Id.ValueString(), data.ForceDetachOriginId) {
+		return
+	}
+
 	response, err := r.Client.OriginDeleteAwsWithResponse(ctx, data.Id.ValueString())
 	if err != nil {
 		diags.AddError(errMessage, err.Error())
@@ -116,6 +173,28 @@ func (r *AwsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	util.ValidateDeletionResponse(diags, response, errMessage)
 }
 
+// getAccessKeySecret returns the secret from either the "access_key_secret" attribute or the write-only
+// "access_key_secret_wo" attribute, which is available only in the configuration.
+func getAccessKeySecret(
+	ctx context.Context,
+	diags *diag.Diagnostics,
+	config tfsdk.Config,
+	data AwsModel,
+) (types.String, bool) {
+	if !data.AccessKeySecret.IsNull() {
+		return data.AccessKeySecret, true
+	}
+
+	var accessKeySecretWo types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("access_key_secret_wo"), &accessKeySecretWo)...)
+
+	if diags.HasError() {
+		return types.StringNull(), false
+	}
+
+	return accessKeySecretWo, true
+}
+
 func (*AwsResource) ImportState(
 	ctx context.Context,
 	req resource.ImportStateRequest,
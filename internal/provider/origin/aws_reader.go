@@ -8,10 +8,25 @@ import (
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
 	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type AwsReader struct{}
 
+func NewAwsDataSourceReader() util.Reader {
+	toModel := func(data AwsDataSourceModel) AwsModel {
+		return AwsModel{AwsBaseModel: data.AwsBaseModel}
+	}
+	fromModel := func(model AwsModel) AwsDataSourceModel {
+		return AwsDataSourceModel{
+			AwsBaseModel:    model.AwsBaseModel,
+			AccessKeySecret: model.AccessKeySecret,
+		}
+	}
+
+	return util.NewDataSourceReader(&AwsReader{}, toModel, fromModel)
+}
+
 func (*AwsReader) ErrMessage() string {
 	return "Failed to fetch AWS Origin"
 }
@@ -48,6 +63,10 @@ func (r *AwsReader) Process(
 			AccessKeyId: util.NullableToStringValue(detail.AwsAccessKeyId),
 			Region:      util.NullableToStringValue(detail.AwsRegion),
 		},
-		AccessKeySecret: model.AccessKeySecret,
+		AccessKeySecret:          model.AccessKeySecret,
+		AccessKeySecretWo:        types.StringNull(),
+		AccessKeySecretWoVersion: model.AccessKeySecretWoVersion,
+		DeletionProtection:       model.DeletionProtection,
+		ForceDetachOriginId:      model.ForceDetachOriginId,
 	}
 }
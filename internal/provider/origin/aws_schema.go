@@ -2,6 +2,8 @@ package origin
 
 import (
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -12,6 +14,17 @@ import (
 type AwsModel struct {
 	AwsBaseModel
 
+	AccessKeySecret          types.String `tfsdk:"access_key_secret"`
+	AccessKeySecretWo        types.String `tfsdk:"access_key_secret_wo"`
+	AccessKeySecretWoVersion types.Int64  `tfsdk:"access_key_secret_wo_version"`
+	DeletionProtection       types.Bool   `tfsdk:"deletion_protection"`
+	ForceDetachOriginId      types.String `tfsdk:"force_detach_origin_id"`
+}
+
+// AwsDataSourceModel leaves out the resource-only attributes (see AwsResourceOnlyAttrs).
+type AwsDataSourceModel struct {
+	AwsBaseModel
+
 	AccessKeySecret types.String `tfsdk:"access_key_secret"`
 }
 
@@ -23,6 +36,14 @@ type AwsBaseModel struct {
 	Region      types.String `tfsdk:"region"`
 }
 
+// AwsResourceOnlyAttrs are left out from the data source schema.
+var AwsResourceOnlyAttrs = []string{
+	"access_key_secret_wo",
+	"access_key_secret_wo_version",
+	"deletion_protection",
+	"force_detach_origin_id",
+}
+
 func CreateAwsResourceSchema() schema.Schema {
 	s := CreateAwsBaseResourceSchema()
 	s.Attributes["access_key_secret"] = schema.StringAttribute{
@@ -31,8 +52,31 @@ func CreateAwsResourceSchema() schema.Schema {
 		Sensitive:   true,
 		Validators: []validator.String{
 			stringvalidator.AlsoRequires(path.MatchRoot("access_key_id"), path.MatchRoot("region")),
+			stringvalidator.ConflictsWith(path.MatchRoot("access_key_secret_wo")),
+		},
+	}
+	s.Attributes["access_key_secret_wo"] = schema.StringAttribute{
+		Description: `Write-only alternative to "access_key_secret" which is never stored in the state ` +
+			`(requires Terraform 1.11 or later). Change "access_key_secret_wo_version" to update the secret.`,
+		Optional:  true,
+		WriteOnly: true,
+		Validators: []validator.String{
+			stringvalidator.AlsoRequires(
+				path.MatchRoot("access_key_id"),
+				path.MatchRoot("region"),
+				path.MatchRoot("access_key_secret_wo_version"),
+			),
 		},
 	}
+	s.Attributes["access_key_secret_wo_version"] = schema.Int64Attribute{
+		Description: `Version of "access_key_secret_wo"; change it whenever the write-only secret changes`,
+		Optional:    true,
+		Validators: []validator.Int64{
+			int64validator.AlsoRequires(path.MatchRoot("access_key_secret_wo")),
+		},
+	}
+	s.Attributes["deletion_protection"] = util.DeletionProtectionSchemaAttr()
+	s.Attributes["force_detach_origin_id"] = forceDetachOriginIdSchemaAttr()
 
 	return s
 }
@@ -45,16 +89,26 @@ func CreateAwsBaseResourceSchema() schema.Schema {
 				Description: "AWS access key ID",
 				Optional:    true,
 				Validators: []validator.String{
-					stringvalidator.AlsoRequires(path.MatchRoot("access_key_secret"), path.MatchRoot("region")),
+					stringvalidator.AlsoRequires(path.MatchRoot("region")),
+					accessKeySecretValidator(),
 				},
 			},
 			"region": schema.StringAttribute{
 				Description: "AWS region",
 				Optional:    true,
 				Validators: []validator.String{
-					stringvalidator.AlsoRequires(path.MatchRoot("access_key_secret"), path.MatchRoot("access_key_id")),
+					stringvalidator.AlsoRequires(path.MatchRoot("access_key_id")),
+					accessKeySecretValidator(),
 				},
 			},
 		},
 	}))
 }
+
+// accessKeySecretValidator requires the access key secret to be set via either the regular or the write-only attribute.
+func accessKeySecretValidator() validator.String {
+	return stringvalidator.Any(
+		stringvalidator.AlsoRequires(path.MatchRoot("access_key_secret")),
+		stringvalidator.AlsoRequires(path.MatchRoot("access_key_secret_wo")),
+	)
+}
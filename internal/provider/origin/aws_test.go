@@ -255,6 +255,70 @@ func TestAccOrigin_AwsResource_Import_NoKey(t *testing.T) {
 	)
 }
 
+func TestAccOrigin_AwsResource_WriteOnlySecret(t *testing.T) {
+	const rsc = "cdn77_origin_aws.aws"
+	client := acctest.GetClient(t)
+	var originId string
+
+	acctest.RunWriteOnly(t, acctest.CheckOriginDestroyed(client, origin.TypeAws),
+		resource.TestStep{
+			Config: `resource "cdn77_origin_aws" "aws" {
+				label = "some label"
+				url = "http://my-totally-random-custom-host.com"
+				access_key_id = "keyid"
+				access_key_secret = "secret"
+				access_key_secret_wo = "secret"
+				access_key_secret_wo_version = 1
+				region = "eu"
+			}`,
+			ExpectError: regexp.MustCompile(`(?s)Invalid Attribute Combination.*access_key_secret_wo`),
+			PlanOnly:    true,
+		},
+		resource.TestStep{
+			Config: `resource "cdn77_origin_aws" "aws" {
+				label = "some label"
+				url = "http://my-totally-random-custom-host.com"
+				access_key_id = "keyid"
+				access_key_secret_wo = "secret"
+				access_key_secret_wo_version = 1
+				region = "eu"
+			}`,
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionCreate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr(rsc, "id", &originId),
+				resource.TestCheckResourceAttr(rsc, "access_key_id", "keyid"),
+				resource.TestCheckResourceAttr(rsc, "access_key_secret_wo_version", "1"),
+				resource.TestCheckResourceAttr(rsc, "region", "eu"),
+				resource.TestCheckNoResourceAttr(rsc, "access_key_secret"),
+				resource.TestCheckNoResourceAttr(rsc, "access_key_secret_wo"),
+				checkAws(client, &originId, func(o *cdn77.S3OriginDetail) error {
+					return errors.Join(
+						acctest.NullFieldEqual("access_key_id", o.AwsAccessKeyId, "keyid"),
+						acctest.NullFieldEqual("region", o.AwsRegion, "eu"),
+					)
+				}),
+			),
+		},
+		resource.TestStep{
+			Config: `resource "cdn77_origin_aws" "aws" {
+				label = "some label"
+				url = "http://my-totally-random-custom-host.com"
+				access_key_id = "keyid"
+				access_key_secret_wo = "another secret"
+				access_key_secret_wo_version = 2
+				region = "eu"
+			}`,
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionUpdate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAttr(rsc, "id", &originId),
+				resource.TestCheckResourceAttr(rsc, "access_key_secret_wo_version", "2"),
+				resource.TestCheckNoResourceAttr(rsc, "access_key_secret"),
+				resource.TestCheckNoResourceAttr(rsc, "access_key_secret_wo"),
+			),
+		},
+	)
+}
+
 func TestAccOrigin_AwsDataSource_OnlyRequiredFields(t *testing.T) {
 	const nonExistingOriginId = "bcd7b5bb-a044-4611-82e4-3f3b2a3cda13"
 	const rsc = "data.cdn77_origin_aws.aws"
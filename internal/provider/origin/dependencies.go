@@ -0,0 +1,55 @@
+package origin
+
+import (
+	"context"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func forceDetachOriginIdSchemaAttr() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Description: "ID of the Origin which CDNs using this Origin are switched to before this Origin is deleted. " +
+			"Without it, the deletion fails if any CDN uses this Origin.",
+	}
+}
+
+// detachCdns makes sure no CDN uses the Origin before it's deleted; see util.DetachCdns.
+func detachCdns(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	errMessage string,
+	originId string,
+	replacementId types.String,
+) bool {
+	response, err := client.CdnListWithResponse(ctx)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return false
+	}
+
+	var cdns []util.CdnReference
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(list *[]cdn77.CdnSummary) {
+		for _, c := range *list {
+			if util.NullableToStringValue(c.OriginId).ValueString() == originId {
+				cdns = append(cdns, util.CdnReference{Id: c.Id, Label: c.Label})
+			}
+		}
+	})
+
+	if diags.HasError() {
+		return false
+	}
+
+	const replacementAttr = "force_detach_origin_id"
+	request := cdn77.CdnEditJSONRequestBody{OriginId: replacementId.ValueStringPointer()}
+
+	return util.DetachCdns(ctx, client, diags, errMessage, "Origin", cdns, replacementAttr, replacementId, request)
+}
@@ -0,0 +1,151 @@
+package origin_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/oapi-codegen/nullable"
+)
+
+func TestUrlResource_DeleteDetachesCdns(t *testing.T) {
+	const originId = "origin-id"
+
+	usingCdns := []cdn77.CdnSummary{
+		{Id: 1, Label: "first", OriginId: nullable.NewNullableWithValue(originId)},
+		{Id: 2, Label: "other", OriginId: nullable.NewNullableWithValue("other-origin-id")},
+	}
+
+	testCases := map[string]struct {
+		cdns                []cdn77.CdnSummary
+		forceDetachOriginId types.String
+		originMissing       bool
+		expectedEdits       []int
+		expectedDeleted     bool
+		expectedError       string
+	}{
+		"refused while used by CDNs": {
+			cdns:                usingCdns,
+			forceDetachOriginId: types.StringNull(),
+			expectedError:       `used by the following CDNs: 1 (first). Switch them to another Origin first`,
+		},
+		"CDNs detached to replacement": {
+			cdns:                usingCdns,
+			forceDetachOriginId: types.StringValue("replacement-id"),
+			expectedEdits:       []int{1},
+			expectedDeleted:     true,
+		},
+		"origin already deleted": {
+			forceDetachOriginId: types.StringNull(),
+			originMissing:       true,
+			expectedDeleted:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := &originClient{cdns: tc.cdns, originMissing: tc.originMissing}
+			baseResource := util.NewBaseResource("origin_url", origin.CreateUrlResourceSchema, nil)
+			baseResource.Client = client
+			r := &origin.UrlResource{BaseResource: baseResource}
+
+			s := origin.CreateUrlResourceSchema()
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+			diags := state.SetAttribute(t.Context(), path.Root("id"), types.StringValue(originId))
+			diags.Append(
+				state.SetAttribute(t.Context(), path.Root("force_detach_origin_id"), tc.forceDetachOriginId)...,
+			)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			var resp resource.DeleteResponse
+			r.Delete(t.Context(), resource.DeleteRequest{State: state}, &resp)
+
+			if !slices.Equal(client.edits, tc.expectedEdits) {
+				t.Errorf("expected edited CDNs %v, got %v", tc.expectedEdits, client.edits)
+			}
+
+			for _, id := range client.originIds {
+				if id != tc.forceDetachOriginId.ValueString() {
+					t.Errorf("expected CDNs to be switched to %s, got %s", tc.forceDetachOriginId, id)
+				}
+			}
+
+			if client.deleted != tc.expectedDeleted {
+				t.Errorf("expected the Origin to be deleted: %t, got %t", tc.expectedDeleted, client.deleted)
+			}
+
+			if tc.expectedError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("unexpected error: %v", resp.Diagnostics)
+				}
+
+				return
+			}
+
+			if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), tc.expectedError) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// originClient lists the given CDNs, records the CDN edits and deletes the Origin; the deletion responds with 404 if
+// originMissing is set.
+type originClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	cdns          []cdn77.CdnSummary
+	originMissing bool
+	edits         []int
+	originIds     []string
+	deleted       bool
+}
+
+func (c *originClient) CdnListWithResponse(
+	context.Context,
+	...cdn77.RequestEditorFn,
+) (*cdn77.CdnListResponse, error) {
+	return &cdn77.CdnListResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &c.cdns}, nil
+}
+
+func (c *originClient) CdnEditWithResponse(
+	_ context.Context,
+	id int,
+	body cdn77.CdnEditJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnEditResponse, error) {
+	c.edits = append(c.edits, id)
+	c.originIds = append(c.originIds, *body.OriginId)
+
+	return &cdn77.CdnEditResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+func (c *originClient) OriginDeleteUrlWithResponse(
+	context.Context,
+	string,
+	...cdn77.RequestEditorFn,
+) (*cdn77.OriginDeleteUrlResponse, error) {
+	c.deleted = true
+
+	if c.originMissing {
+		return &cdn77.OriginDeleteUrlResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+			JSON404:      &cdn77.Errors{Errors: []string{"Origin not found"}},
+		}, nil
+	}
+
+	return &cdn77.OriginDeleteUrlResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
@@ -35,37 +35,73 @@ func (r *ObjectStorageResource) Create(ctx context.Context, req resource.CreateR
 
 	const errMessage = "Failed to create Object Storage Origin"
 
+	marker, err := util.NewIdempotencyMarker()
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
 	request := cdn77.OriginCreateObjectStorageJSONRequestBody{
 		Label:      data.Label.ValueString(),
-		Note:       util.StringValueToNullable(data.Note),
+		Note:       marker.Mark(util.StringValueToNullable(data.Note)),
 		BucketName: data.BucketName.ValueString(),
 		Acl:        cdn77.AclType(data.Acl.ValueString()),
 		ClusterId:  data.ClusterId.ValueString(),
 	}
 
 	response, err := r.Client.OriginCreateObjectStorageWithResponse(ctx, request)
-	if err != nil {
-		diags.AddError(errMessage, err.Error())
 
-		return
+	var detail *cdn77.ObjectStorageOriginDetail
+
+	if util.IsLostResponse(response, err) {
+		var cancel context.CancelFunc
+		ctx, cancel = util.LostResponseContext(ctx)
+		defer cancel()
+
+		idAndNote := func(o cdn77.ObjectStorageOriginDetail) (string, nullable.Nullable[string]) { return o.Id, o.Note }
+		if detail = findCreatedOrigin(ctx, r.Client, diags, marker, idAndNote); diags.HasError() {
+			return
+		}
 	}
 
-	util.ProcessResponse(diags, response, errMessage, response.JSON201, func(detail *cdn77.ObjectStorageOriginDetail) {
-		data.Id = types.StringValue(detail.Id)
-		data.UrlModel = shared.NewUrlModel(
-			ctx,
-			string(detail.Scheme),
-			detail.Host,
-			detail.Port,
-			nullable.NewNullNullable[string](),
-		)
-		data.Usage = &ObjectStorageUsageModel{
-			Files:     util.IntPointerToInt64Value(detail.Usage.FileCount),
-			SizeBytes: util.IntPointerToInt64Value(detail.Usage.SizeBytes),
+	if detail == nil {
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
+
+			return
 		}
 
-		diags.Append(resp.State.Set(ctx, data)...)
-	})
+		util.ProcessResponse(diags, response, errMessage, response.JSON201, func(d *cdn77.ObjectStorageOriginDetail) {
+			detail = d
+		})
+
+		if diags.HasError() {
+			return
+		}
+	}
+
+	data.Id = types.StringValue(detail.Id)
+	data.UrlModel = shared.NewUrlModel(
+		ctx,
+		string(detail.Scheme),
+		detail.Host,
+		detail.Port,
+		nullable.NewNullNullable[string](),
+	)
+	data.Usage = &ObjectStorageUsageModel{
+		Files:     util.IntPointerToInt64Value(detail.Usage.FileCount),
+		SizeBytes: util.IntPointerToInt64Value(detail.Usage.SizeBytes),
+	}
+
+	editNote := func(note nullable.Nullable[string]) (util.Response, error) {
+		editRequest := cdn77.OriginEditObjectStorageJSONRequestBody{Note: note}
+
+		return r.Client.OriginEditObjectStorageWithResponse(ctx, data.Id.ValueString(), editRequest)
+	}
+	removeIdempotencyMarker(diags, data.SharedModel, editNote)
+
+	diags.Append(resp.State.Set(ctx, data)...)
 }
 
 func (r *ObjectStorageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -104,6 +140,14 @@ func (r *ObjectStorageResource) Delete(ctx context.Context, req resource.DeleteR
 
 	const errMessage = "Failed to delete Object Storage Origin"
 
+	if util.IsDeletionProtected(diags, data.DeletionProtection, errMessage) {
+		return
+	}
+
+	if !detachCdns(ctx, r.Client, diags, errMessage, data.Id.ValueString(), data.ForceDetachOriginId) {
+		return
+	}
+
 	response, err := r.Client.OriginDeleteObjectStorageWithResponse(ctx, data.Id.ValueString())
 	if err != nil {
 		diags.AddError(errMessage, err.Error())
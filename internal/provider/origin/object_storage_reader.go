@@ -14,6 +14,21 @@ import (
 
 type ObjectStorageReader struct{}
 
+func NewObjectStorageDataSourceReader() util.Reader {
+	toModel := func(data ObjectStorageDataSourceModel) ObjectStorageModel {
+		return ObjectStorageModel{ObjectStorageBaseModel: data.ObjectStorageBaseModel}
+	}
+	fromModel := func(model ObjectStorageModel) ObjectStorageDataSourceModel {
+		return ObjectStorageDataSourceModel{
+			ObjectStorageBaseModel: model.ObjectStorageBaseModel,
+			Acl:                    model.Acl,
+			ClusterId:              model.ClusterId,
+		}
+	}
+
+	return util.NewDataSourceReader(&ObjectStorageReader{}, toModel, fromModel)
+}
+
 func (*ObjectStorageReader) ErrMessage() string {
 	return "Failed to fetch Object Storage Origin"
 }
@@ -58,7 +73,9 @@ func (r *ObjectStorageReader) Process(
 				SizeBytes: util.IntPointerToInt64Value(detail.Usage.SizeBytes),
 			},
 		},
-		Acl:       model.Acl,
-		ClusterId: model.ClusterId,
+		Acl:                 model.Acl,
+		ClusterId:           model.ClusterId,
+		DeletionProtection:  model.DeletionProtection,
+		ForceDetachOriginId: model.ForceDetachOriginId,
 	}
 }
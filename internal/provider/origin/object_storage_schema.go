@@ -4,6 +4,7 @@ import (
 	"regexp"
 
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,6 +20,16 @@ import (
 type ObjectStorageModel struct {
 	ObjectStorageBaseModel
 
+	Acl                 types.String `tfsdk:"acl"`
+	ClusterId           types.String `tfsdk:"cluster_id"`
+	DeletionProtection  types.Bool   `tfsdk:"deletion_protection"`
+	ForceDetachOriginId types.String `tfsdk:"force_detach_origin_id"`
+}
+
+// ObjectStorageDataSourceModel leaves out the resource-only attributes (see ObjectStorageResourceOnlyAttrs).
+type ObjectStorageDataSourceModel struct {
+	ObjectStorageBaseModel
+
 	Acl       types.String `tfsdk:"acl"`
 	ClusterId types.String `tfsdk:"cluster_id"`
 }
@@ -36,6 +47,9 @@ type ObjectStorageUsageModel struct {
 	SizeBytes types.Int64 `tfsdk:"size_bytes"`
 }
 
+// ObjectStorageResourceOnlyAttrs are left out from the data source schema.
+var ObjectStorageResourceOnlyAttrs = []string{"deletion_protection", "force_detach_origin_id"}
+
 func CreateObjectStorageResourceSchema() schema.Schema {
 	s := CreateObjectStorageBaseResourceSchema()
 	s.Attributes["acl"] = schema.StringAttribute{
@@ -51,6 +65,8 @@ func CreateObjectStorageResourceSchema() schema.Schema {
 		Required:      true,
 		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 	}
+	s.Attributes["deletion_protection"] = util.DeletionProtectionSchemaAttr()
+	s.Attributes["force_detach_origin_id"] = forceDetachOriginIdSchemaAttr()
 
 	return s
 }
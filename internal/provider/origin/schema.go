@@ -12,6 +12,7 @@ import (
 const (
 	TypeAws           = "aws"
 	TypeObjectStorage = "object-storage"
+	TypeStorage       = "storage"
 	TypeUrl           = "url"
 )
 
@@ -25,7 +26,7 @@ func NewSharedModel(id types.String, label string, note nullable.Nullable[string
 	return SharedModel{
 		Id:    id,
 		Label: types.StringValue(label),
-		Note:  util.NullableToStringValue(note),
+		Note:  util.NullableToStringValue(util.WithoutIdempotencyMarkers(note)),
 	}
 }
 
@@ -0,0 +1,12 @@
+package origin
+
+import (
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+var _ datasource.DataSourceWithConfigure = &StorageDataSource{}
+
+type StorageDataSource struct {
+	*util.BaseDataSource
+}
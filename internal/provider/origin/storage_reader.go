@@ -0,0 +1,57 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type StorageReader struct{}
+
+func (*StorageReader) ErrMessage() string {
+	return "Failed to fetch Storage Origin"
+}
+
+func (*StorageReader) Fetch(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	model StorageModel,
+) (*cdn77.OriginDetailStorageResponse, *cdn77.StorageOriginDetail, error) {
+	response, err := client.OriginDetailStorageWithResponse(ctx, model.Id.ValueString())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, response.JSON200, nil
+}
+
+func (r *StorageReader) Process(
+	_ context.Context,
+	model StorageModel,
+	detail *cdn77.StorageOriginDetail,
+	diags *diag.Diagnostics,
+) StorageModel {
+	if detail.Type != TypeStorage {
+		diags.AddError(r.ErrMessage(), fmt.Sprintf("Origin with id=\"%s\" is not a Storage Origin", detail.Id))
+
+		return model
+	}
+
+	return NewStorageModel(model.Id, detail)
+}
+
+func NewStorageModel(id types.String, detail *cdn77.StorageOriginDetail) StorageModel {
+	return StorageModel{
+		SharedModel: NewSharedModel(id, detail.Label, detail.Note),
+		LocationId:  types.StringPointerValue(detail.Server.Id),
+		Location:    types.StringPointerValue(detail.Server.Location),
+		Usage: &StorageUsageModel{
+			Files:     util.IntPointerToInt64Value(detail.Usage.Nodes),
+			SizeBytes: util.IntPointerToInt64Value(detail.Usage.Space),
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package origin
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type StorageModel struct {
+	SharedModel
+
+	LocationId types.String       `tfsdk:"location_id"`
+	Location   types.String       `tfsdk:"location"`
+	Usage      *StorageUsageModel `tfsdk:"usage"`
+}
+
+type StorageUsageModel struct {
+	Files     types.Int64 `tfsdk:"files"`
+	SizeBytes types.Int64 `tfsdk:"size_bytes"`
+}
+
+// CreateStorageResourceSchema describes CDN77 Storage Origins. The API doesn't allow creating them, so the schema
+// is only used to derive the data source schemas.
+func CreateStorageResourceSchema() schema.Schema {
+	return WithSharedSchemaAttrs(schema.Schema{
+		MarkdownDescription: "Storage Origin data source allows you to read your CDN77 Storage Origins",
+		Attributes: map[string]schema.Attribute{
+			"location_id": schema.StringAttribute{
+				Description: "ID of the CDN77 Storage location",
+				Computed:    true,
+			},
+			"location": schema.StringAttribute{
+				Description: "Name of the CDN77 Storage location",
+				Computed:    true,
+			},
+			"usage": schema.SingleNestedAttribute{
+				Attributes: map[string]schema.Attribute{
+					"files": schema.Int64Attribute{
+						Computed:    true,
+						Description: "Number of files stored on the CDN77 Storage",
+					},
+					"size_bytes": schema.Int64Attribute{
+						Computed:    true,
+						Description: "CDN77 Storage space used in bytes",
+					},
+				},
+				Computed:    true,
+				Description: "Usage statistics of the CDN77 Storage",
+			},
+		},
+	})
+}
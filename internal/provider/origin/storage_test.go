@@ -0,0 +1,26 @@
+package origin_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// CDN77 Storage Origins can't be created through the API, so only the error path is covered here.
+func TestAccOrigin_StorageDataSource_NotFound(t *testing.T) {
+	const nonExistingOriginId = "bcd7b5bb-a044-4611-82e4-3f3b2a3cda13"
+
+	acctest.Run(t, nil, resource.TestStep{
+		Config:      acctest.Config(storageDataSourceConfig, "id", nonExistingOriginId),
+		ExpectError: regexp.MustCompile(fmt.Sprintf(`.*?"%s".*?not found.*?`, nonExistingOriginId)),
+	})
+}
+
+const storageDataSourceConfig = `
+data "cdn77_origin_storage" "storage" {
+  id = "{id}"
+}
+`
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oapi-codegen/nullable"
 )
 
 var (
@@ -34,10 +35,17 @@ func (r *UrlResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	const errMessage = "Failed to create URL Origin"
 
+	marker, err := util.NewIdempotencyMarker()
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
 	scheme, host, port, basePath := data.UrlModel.Parts(ctx)
 	request := cdn77.OriginCreateUrlJSONRequestBody{
 		Label:   data.Label.ValueString(),
-		Note:    util.StringValueToNullable(data.Note),
+		Note:    marker.Mark(util.StringValueToNullable(data.Note)),
 		Scheme:  cdn77.OriginScheme(scheme),
 		Host:    host,
 		Port:    port,
@@ -45,17 +53,46 @@ func (r *UrlResource) Create(ctx context.Context, req resource.CreateRequest, re
 	}
 
 	response, err := r.Client.OriginCreateUrlWithResponse(ctx, request)
-	if err != nil {
-		diags.AddError(errMessage, err.Error())
 
-		return
+	var detail *cdn77.UrlOriginDetail
+
+	if util.IsLostResponse(response, err) {
+		var cancel context.CancelFunc
+		ctx, cancel = util.LostResponseContext(ctx)
+		defer cancel()
+
+		idAndNote := func(o cdn77.UrlOriginDetail) (string, nullable.Nullable[string]) { return o.Id, o.Note }
+		if detail = findCreatedOrigin(ctx, r.Client, diags, marker, idAndNote); diags.HasError() {
+			return
+		}
 	}
 
-	util.ProcessResponse(diags, response, errMessage, response.JSON201, func(detail *cdn77.UrlOriginDetail) {
-		data.Id = types.StringValue(detail.Id)
+	if detail == nil {
+		if err != nil {
+			diags.AddError(errMessage, err.Error())
 
-		diags.Append(resp.State.Set(ctx, data)...)
-	})
+			return
+		}
+
+		util.ProcessResponse(diags, response, errMessage, response.JSON201, func(d *cdn77.UrlOriginDetail) {
+			detail = d
+		})
+
+		if diags.HasError() {
+			return
+		}
+	}
+
+	data.Id = types.StringValue(detail.Id)
+
+	editNote := func(note nullable.Nullable[string]) (util.Response, error) {
+		editRequest := cdn77.OriginEditUrlJSONRequestBody{Note: note}
+
+		return r.Client.OriginEditUrlWithResponse(ctx, data.Id.ValueString(), editRequest)
+	}
+	removeIdempotencyMarker(diags, data.SharedModel, editNote)
+
+	diags.Append(resp.State.Set(ctx, data)...)
 }
 
 func (r *UrlResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -100,6 +137,14 @@ func (r *UrlResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	const errMessage = "Failed to delete URL Origin"
 
+	if util.IsDeletionProtected(diags, data.DeletionProtection, errMessage) {
+		return
+	}
+
+	if !detachCdns(ctx, r.Client, diags, errMessage, data.Id.ValueString(), data.ForceDetachOriginId) {
+		return
+	}
+
 	response, err := r.Client.OriginDeleteUrlWithResponse(ctx, data.Id.ValueString())
 	if err != nil {
 		diags.AddError(errMessage, err.Error())
@@ -154,18 +199,20 @@ func (r *UrlResource) MoveState(context.Context) []resource.StateMover {
 				}
 
 				model := UrlModel{
-					SharedModel: NewSharedModel(
-						oldModel.Id,
-						oldModel.Label.ValueString(),
-						util.StringValueToNullable(oldModel.Note),
-					),
-					UrlModel: shared.NewUrlModel(
-						ctx,
-						oldModel.Scheme.ValueString(),
-						oldModel.Host.ValueString(),
-						util.Int64ValueToNullable[int](oldModel.Port),
-						util.StringValueToNullable(oldModel.BaseDir),
-					),
+					UrlBaseModel: UrlBaseModel{
+						SharedModel: NewSharedModel(
+							oldModel.Id,
+							oldModel.Label.ValueString(),
+							util.StringValueToNullable(oldModel.Note),
+						),
+						UrlModel: shared.NewUrlModel(
+							ctx,
+							oldModel.Scheme.ValueString(),
+							oldModel.Host.ValueString(),
+							util.Int64ValueToNullable[int](oldModel.Port),
+							util.StringValueToNullable(oldModel.BaseDir),
+						),
+					},
 				}
 
 				diags.Append(resp.TargetState.Set(ctx, model)...)
@@ -6,11 +6,23 @@ import (
 
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 )
 
 type UrlReader struct{}
 
+func NewUrlDataSourceReader() util.Reader {
+	toModel := func(data UrlDataSourceModel) UrlModel {
+		return UrlModel{UrlBaseModel: data.UrlBaseModel}
+	}
+	fromModel := func(model UrlModel) UrlDataSourceModel {
+		return UrlDataSourceModel{UrlBaseModel: model.UrlBaseModel}
+	}
+
+	return util.NewDataSourceReader(&UrlReader{}, toModel, fromModel)
+}
+
 func (*UrlReader) ErrMessage() string {
 	return "Failed to fetch URL Origin"
 }
@@ -41,7 +53,11 @@ func (r *UrlReader) Process(
 	}
 
 	return UrlModel{
-		SharedModel: NewSharedModel(model.Id, detail.Label, detail.Note),
-		UrlModel:    shared.NewUrlModel(ctx, string(detail.Scheme), detail.Host, detail.Port, detail.BaseDir),
+		UrlBaseModel: UrlBaseModel{
+			SharedModel: NewSharedModel(model.Id, detail.Label, detail.Note),
+			UrlModel:    shared.NewUrlModel(ctx, string(detail.Scheme), detail.Host, detail.Port, detail.BaseDir),
+		},
+		DeletionProtection:  model.DeletionProtection,
+		ForceDetachOriginId: model.ForceDetachOriginId,
 	}
 }
@@ -2,15 +2,40 @@ package origin
 
 import (
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type UrlModel struct {
+	UrlBaseModel
+
+	DeletionProtection  types.Bool   `tfsdk:"deletion_protection"`
+	ForceDetachOriginId types.String `tfsdk:"force_detach_origin_id"`
+}
+
+// UrlDataSourceModel leaves out the resource-only attributes (see UrlResourceOnlyAttrs).
+type UrlDataSourceModel struct {
+	UrlBaseModel
+}
+
+type UrlBaseModel struct {
 	SharedModel
 	shared.UrlModel
 }
 
+// UrlResourceOnlyAttrs are left out from the data source schema.
+var UrlResourceOnlyAttrs = []string{"deletion_protection", "force_detach_origin_id"}
+
 func CreateUrlResourceSchema() schema.Schema {
+	s := CreateUrlBaseResourceSchema()
+	s.Attributes["deletion_protection"] = util.DeletionProtectionSchemaAttr()
+	s.Attributes["force_detach_origin_id"] = forceDetachOriginIdSchemaAttr()
+
+	return s
+}
+
+func CreateUrlBaseResourceSchema() schema.Schema {
 	return WithSharedSchemaAttrs(shared.WithUrlSchemaAttrs(schema.Schema{
 		MarkdownDescription: "URL Origin resource allows you to manage your custom URL Origins",
 		Attributes:          map[string]schema.Attribute{},
@@ -172,6 +172,33 @@ func TestAccOrigin_UrlResource_Import(t *testing.T) {
 	)
 }
 
+func TestAccOrigin_UrlResource_DeletionProtection(t *testing.T) {
+	client := acctest.GetClient(t)
+	rsc := "cdn77_origin_url.url"
+	config := func(deletionProtection bool) string {
+		return fmt.Sprintf(`resource "cdn77_origin_url" "url" {
+			label = "some label"
+			url = "http://my-totally-random-custom-host.com"
+			deletion_protection = %t
+		}`, deletionProtection)
+	}
+
+	acctest.Run(t, acctest.CheckOriginDestroyed(client, origin.TypeUrl),
+		resource.TestStep{
+			Config: config(true),
+			Check:  resource.TestCheckResourceAttr(rsc, "deletion_protection", "true"),
+		},
+		resource.TestStep{
+			Config:      `# the origin is removed from the configuration`,
+			ExpectError: regexp.MustCompile(`Deletion protection is enabled`),
+		},
+		resource.TestStep{
+			Config:           config(false),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionUpdate),
+		},
+	)
+}
+
 func TestAccOrigin_UrlDataSource_OnlyRequiredFields(t *testing.T) {
 	const nonExistingOriginId = "bcd7b5bb-a044-4611-82e4-3f3b2a3cda13"
 	const rsc = "data.cdn77_origin_url.url"
@@ -11,9 +11,14 @@ import (
 
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/mapping"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
 	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/ssl"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -21,7 +26,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &Cdn77Provider{}
+var (
+	_ provider.ProviderWithEphemeralResources = &Cdn77Provider{}
+	_ provider.ProviderWithFunctions          = &Cdn77Provider{}
+)
 
 type Cdn77Provider struct {
 	// version is set to the provider version on release, "dev" when the provider is built and ran locally,
@@ -122,6 +130,7 @@ func (p *Cdn77Provider) Configure(
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 }
 
 func (*Cdn77Provider) getConfig(
@@ -181,10 +190,13 @@ func (*Cdn77Provider) getConfig(
 func (*Cdn77Provider) Resources(context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		mapping.ResourceFactory(mapping.Cdn),
+		mapping.ResourceFactory(mapping.CdnCname),
+		mapping.ResourceFactory(mapping.CdnRule),
 		mapping.ResourceFactory(mapping.OriginAws),
 		mapping.ResourceFactory(mapping.OriginObjectStorage),
 		mapping.ResourceFactory(mapping.OriginUrl),
 		mapping.ResourceFactory(mapping.Ssl),
+		mapping.ResourceFactory(mapping.SslRotation),
 	}
 }
 
@@ -192,13 +204,32 @@ func (*Cdn77Provider) DataSources(context.Context) []func() datasource.DataSourc
 	return []func() datasource.DataSource{
 		mapping.DataSourceFactory(mapping.Cdn),
 		mapping.DataSourceFactory(mapping.Cdns),
+		cdn.NewConditionalFeaturesEvaluateDataSource,
 		mapping.DataSourceFactory(mapping.ObjectStorages),
 		mapping.DataSourceFactory(mapping.OriginAws),
 		mapping.DataSourceFactory(mapping.OriginObjectStorage),
+		mapping.DataSourceFactory(mapping.OriginStorage),
 		mapping.DataSourceFactory(mapping.OriginUrl),
 		origin.NewAllDataSource,
+		cdn.NewOriginUsageDataSource,
 		mapping.DataSourceFactory(mapping.Ssl),
 		mapping.DataSourceFactory(mapping.Ssls),
+		cdn.NewSslUsageDataSource,
+	}
+}
+
+func (*Cdn77Provider) EphemeralResources(context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		cdn.NewSignedUrlEphemeralResource,
+	}
+}
+
+func (*Cdn77Provider) Functions(context.Context) []func() function.Function {
+	return []func() function.Function{
+		cdn.NewSignUrlFunction,
+		shared.NewOriginUrlFunction,
+		shared.NewParseOriginUrlFunction,
+		ssl.NewCertificateInfoFunction,
 	}
 }
 
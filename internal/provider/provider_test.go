@@ -3,9 +3,21 @@ package provider_test
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/cdn77/terraform-provider-cdn77/internal/mapping"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/cdn"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/ssl"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -89,3 +101,78 @@ func TestMain(m *testing.M) {
 
 	resource.TestMain(m)
 }
+
+func TestResource_DeletionProtection(t *testing.T) {
+	resources := []mapping.Resource{
+		mapping.Cdn,
+		mapping.OriginAws,
+		mapping.OriginObjectStorage,
+		mapping.OriginUrl,
+		mapping.Ssl,
+	}
+
+	for _, rsc := range resources {
+		t.Run(string(rsc), func(t *testing.T) {
+			// The resource has no client, so any API call would panic.
+			r := mapping.ResourceFactory(rsc)()
+
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(t.Context(), fwresource.SchemaRequest{}, &schemaResp)
+
+			s := schemaResp.Schema
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+
+			diags := state.SetAttribute(t.Context(), path.Root("deletion_protection"), types.BoolValue(true))
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			var resp fwresource.DeleteResponse
+			r.Delete(t.Context(), fwresource.DeleteRequest{State: state}, &resp)
+
+			if resp.Diagnostics.ErrorsCount() != 1 {
+				t.Fatalf("expected the deletion to be refused, got %v", resp.Diagnostics)
+			}
+
+			d, ok := resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !d.Path().Equal(path.Root("deletion_protection")) {
+				t.Errorf("expected the error to point at deletion_protection, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDataSource_ModelMatchesSchema(t *testing.T) {
+	models := map[mapping.Resource]any{
+		mapping.Cdn:                 cdn.DataSourceModel{},
+		mapping.Cdns:                cdn.AllModel{},
+		mapping.OriginAws:           origin.AwsDataSourceModel{},
+		mapping.OriginObjectStorage: origin.ObjectStorageDataSourceModel{},
+		mapping.OriginUrl:           origin.UrlDataSourceModel{},
+		mapping.Ssl:                 ssl.DataSourceModel{},
+	}
+
+	for rsc, model := range models {
+		t.Run(string(rsc), func(t *testing.T) {
+			var schemaResp datasource.SchemaResponse
+			mapping.DataSourceFactory(rsc)().Schema(t.Context(), datasource.SchemaRequest{}, &schemaResp)
+
+			typ, ok := schemaResp.Schema.Type().TerraformType(t.Context()).(tftypes.Object)
+			if !ok {
+				t.Fatalf("unexpected schema type %s", typ)
+			}
+
+			attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+			for name, attrType := range typ.AttributeTypes {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+			}
+
+			state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(typ, attrs)}
+			target := reflect.New(reflect.TypeOf(model)).Interface()
+
+			if diags := state.Get(t.Context(), target); diags.HasError() {
+				t.Errorf("expected the model to match the data source schema, got %v", diags)
+			}
+		})
+	}
+}
@@ -220,23 +220,7 @@ func (UrlAndUrlPartsPlanModifier) PlanModifyObject(
 		return
 	}
 
-	port := nullable.NewNullNullable[int]()
-	if i, err := strconv.Atoi(u.Port()); err == nil {
-		port.Set(i)
-	}
-
-	u.Path = strings.TrimLeft(u.Path, "/")
-	basePath := util.If(u.Path == "", nullable.NewNullNullable[string](), nullable.NewNullableWithValue[string](u.Path))
-	urlParts := NewUrlPartsModel(u.Scheme, u.Hostname(), port, basePath)
-
-	urlPartsTypes := map[string]attr.Type{
-		"scheme":    types.StringType,
-		"host":      types.StringType,
-		"port":      types.Int32Type,
-		"base_path": types.StringType,
-	}
-
-	urlPartsObject, objDiags := types.ObjectValueFrom(ctx, urlPartsTypes, urlParts)
+	urlPartsObject, objDiags := types.ObjectValueFrom(ctx, urlPartsAttrTypes(), urlPartsFromUrl(u))
 	if objDiags.HasError() {
 		diags.Append(objDiags...)
 
@@ -256,7 +240,6 @@ func (v UrlStringValidator) MarkdownDescription(ctx context.Context) string {
 	return v.Description(ctx)
 }
 
-//nolint:cyclop
 func (UrlStringValidator) ValidateString(
 	_ context.Context,
 	req validator.StringRequest,
@@ -266,16 +249,20 @@ func (UrlStringValidator) ValidateString(
 		return
 	}
 
-	diags := &resp.Diagnostics
-	addErr := func(summary, detail string) {
-		diags.AddAttributeError(req.Path, summary, detail)
-	}
+	validateUrl(req.ConfigValue.ValueString(), func(summary, detail string) {
+		resp.Diagnostics.AddAttributeError(req.Path, summary, detail)
+	})
+}
 
-	u, err := url.Parse(req.ConfigValue.ValueString())
+// validateUrl reports every problem of the URL via addErr and returns the parsed URL if it could be parsed.
+//
+//nolint:cyclop
+func validateUrl(rawUrl string, addErr func(summary, detail string)) *url.URL {
+	u, err := url.Parse(rawUrl)
 	if err != nil {
 		addErr("Invalid URL", fmt.Sprintf("failed to parse URL: %s", err.Error()))
 
-		return
+		return nil
 	}
 
 	if !slices.Contains([]string{"http", "https"}, u.Scheme) {
@@ -315,6 +302,33 @@ func (UrlStringValidator) ValidateString(
 	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
 		addErr("Invalid URL", "URL must not contain user information, query parameters or fragments")
 	}
+
+	return u
+}
+
+func urlPartsFromUrl(u *url.URL) *UrlPartsModel {
+	port := nullable.NewNullNullable[int]()
+	if i, err := strconv.Atoi(u.Port()); err == nil {
+		port.Set(i)
+	}
+
+	basePath := strings.TrimLeft(u.Path, "/")
+
+	return NewUrlPartsModel(
+		u.Scheme,
+		u.Hostname(),
+		port,
+		util.If(basePath == "", nullable.NewNullNullable[string](), nullable.NewNullableWithValue(basePath)),
+	)
+}
+
+func urlPartsAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"scheme":    types.StringType,
+		"host":      types.StringType,
+		"port":      types.Int32Type,
+		"base_path": types.StringType,
+	}
 }
 
 func urlFromParts(urlParts *UrlPartsModel) types.String {
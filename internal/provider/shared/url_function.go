@@ -0,0 +1,142 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &ParseOriginUrlFunction{}
+	_ function.Function = &OriginUrlFunction{}
+)
+
+type ParseOriginUrlFunction struct{}
+
+func NewParseOriginUrlFunction() function.Function {
+	return &ParseOriginUrlFunction{}
+}
+
+func (*ParseOriginUrlFunction) Metadata(
+	_ context.Context,
+	_ function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "parse_origin_url"
+}
+
+func (*ParseOriginUrlFunction) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Parses an origin URL into its parts",
+		MarkdownDescription: "Validates an origin URL the same way the `url` attribute of origin resources does " +
+			"and returns an object with the same attributes as `url_parts` (`scheme`, `host`, `port` and `base_path`).",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "url",
+				MarkdownDescription: "Absolute URL of the origin (e.g. `https://example.com:8080/path`)",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: urlPartsAttrTypes()},
+	}
+}
+
+func (*ParseOriginUrlFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rawUrl string
+	if resp.Error = req.Arguments.Get(ctx, &rawUrl); resp.Error != nil {
+		return
+	}
+
+	u := validateUrl(rawUrl, func(summary, detail string) {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, summary+": "+detail))
+	})
+
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, urlPartsFromUrl(u))
+}
+
+type OriginUrlFunction struct{}
+
+func NewOriginUrlFunction() function.Function {
+	return &OriginUrlFunction{}
+}
+
+func (*OriginUrlFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "origin_url"
+}
+
+func (*OriginUrlFunction) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an origin URL from its parts",
+		MarkdownDescription: "Builds an origin URL from the same parts as the `url_parts` attribute of origin " +
+			"resources and validates it the same way the `url` attribute does.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "scheme",
+				MarkdownDescription: "URL scheme; can be either `http` or `https`",
+			},
+			function.StringParameter{
+				Name:                "host",
+				MarkdownDescription: "Network host; can be a domain name or an IP address",
+			},
+			function.Int32Parameter{
+				Name:                "port",
+				AllowNullValue:      true,
+				MarkdownDescription: "Port number between 1 and 65535; `null` to use the default scheme port",
+			},
+			function.StringParameter{
+				Name:           "base_path",
+				AllowNullValue: true,
+				MarkdownDescription: "Path to the directory where the content is stored (without leading and " +
+					"trailing slashes); `null` for the root directory",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (*OriginUrlFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var urlParts UrlPartsModel
+	if resp.Error = req.Arguments.Get(
+		ctx,
+		&urlParts.Scheme,
+		&urlParts.Host,
+		&urlParts.Port,
+		&urlParts.BasePath,
+	); resp.Error != nil {
+		return
+	}
+
+	if basePath := urlParts.BasePath.ValueString(); !urlParts.BasePath.IsNull() &&
+		!urlBasePathRegexp.MatchString(basePath) {
+		resp.Error = function.NewArgumentFuncError(3, fmt.Sprintf(
+			"Invalid Base Path: %q mustn't begin or end with a slash and must consist only of alphanumeric "+
+				"and following characters: .-_[]+%%*/",
+			basePath,
+		))
+
+		return
+	}
+
+	originUrl := urlFromParts(&urlParts)
+	validateUrl(originUrl.ValueString(), func(summary, detail string) {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewFuncError(summary+": "+detail))
+	})
+
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, originUrl)
+}
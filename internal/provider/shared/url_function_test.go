@@ -0,0 +1,154 @@
+package shared_test
+
+import (
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/shared"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseOriginUrlFunction(t *testing.T) {
+	partsTypes := map[string]attr.Type{
+		"scheme":    types.StringType,
+		"host":      types.StringType,
+		"port":      types.Int32Type,
+		"base_path": types.StringType,
+	}
+	parts := func(scheme string, host string, port types.Int32, basePath types.String) attr.Value {
+		return types.ObjectValueMust(partsTypes, map[string]attr.Value{
+			"scheme":    types.StringValue(scheme),
+			"host":      types.StringValue(host),
+			"port":      port,
+			"base_path": basePath,
+		})
+	}
+
+	testCases := []struct {
+		name     string
+		url      string
+		expected attr.Value
+	}{
+		{
+			name:     "host only",
+			url:      "http://example.com",
+			expected: parts("http", "example.com", types.Int32Null(), types.StringNull()),
+		},
+		{
+			name:     "all parts",
+			url:      "https://example.com:8080/some/path",
+			expected: parts("https", "example.com", types.Int32Value(8080), types.StringValue("some/path")),
+		},
+		{
+			name:     "IP address",
+			url:      "http://192.0.2.1:81",
+			expected: parts("http", "192.0.2.1", types.Int32Value(81), types.StringNull()),
+		},
+		{name: "unsupported scheme", url: "ftp://example.com"},
+		{name: "missing host", url: "https:///path"},
+		{name: "invalid port", url: "http://example.com:70000"},
+		{name: "trailing slash", url: "http://example.com/path/"},
+		{name: "query", url: "http://example.com/path?a=b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tc.url)})}
+			resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(partsTypes))}
+
+			shared.NewParseOriginUrlFunction().Run(t.Context(), req, &resp)
+
+			if tc.expected == nil {
+				if resp.Error == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if result := resp.Result.Value(); !result.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestOriginUrlFunction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scheme   string
+		host     string
+		port     types.Int32
+		basePath types.String
+		expected string
+	}{
+		{
+			name:     "host only",
+			scheme:   "http",
+			host:     "example.com",
+			port:     types.Int32Null(),
+			basePath: types.StringNull(),
+			expected: "http://example.com",
+		},
+		{
+			name:     "all parts",
+			scheme:   "https",
+			host:     "example.com",
+			port:     types.Int32Value(8080),
+			basePath: types.StringValue("some/path"),
+			expected: "https://example.com:8080/some/path",
+		},
+		{
+			name:     "unsupported scheme",
+			scheme:   "ftp",
+			host:     "example.com",
+			port:     types.Int32Null(),
+			basePath: types.StringNull(),
+		},
+		{
+			name:     "invalid port",
+			scheme:   "http",
+			host:     "example.com",
+			port:     types.Int32Value(0),
+			basePath: types.StringNull(),
+		},
+		{
+			name:     "leading slash",
+			scheme:   "http",
+			host:     "example.com",
+			port:     types.Int32Null(),
+			basePath: types.StringValue("/path"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []attr.Value{types.StringValue(tc.scheme), types.StringValue(tc.host), tc.port, tc.basePath}
+			req := function.RunRequest{Arguments: function.NewArgumentsData(args)}
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+
+			shared.NewOriginUrlFunction().Run(t.Context(), req, &resp)
+
+			if tc.expected == "" {
+				if resp.Error == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if result := resp.Result.Value(); !result.Equal(types.StringValue(tc.expected)) {
+				t.Errorf("expected %q, got %s", tc.expected, result)
+			}
+		})
+	}
+}
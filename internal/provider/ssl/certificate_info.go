@@ -0,0 +1,159 @@
+package ssl
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCertificateMissing       = errors.New("no PEM encoded certificate found")
+	ErrCertificateUnexpectedPem = errors.New("unexpected PEM block")
+	ErrPrivateKeyMissing        = errors.New("no PEM encoded private key found")
+	ErrPrivateKeyUnsupported    = errors.New("unsupported private key type")
+)
+
+type CertificateInfo struct {
+	Subject           string   `tfsdk:"subject"`
+	CommonName        string   `tfsdk:"common_name"`
+	Subjects          []string `tfsdk:"subjects"`
+	Issuer            string   `tfsdk:"issuer"`
+	Serial            string   `tfsdk:"serial"`
+	NotBefore         string   `tfsdk:"not_before"`
+	NotAfter          string   `tfsdk:"not_after"`
+	KeyType           string   `tfsdk:"key_type"`
+	KeySize           int64    `tfsdk:"key_size"`
+	Sha256Fingerprint string   `tfsdk:"sha256_fingerprint"`
+	ChainLength       int64    `tfsdk:"chain_length"`
+	KeyMatches        *bool    `tfsdk:"key_matches"`
+}
+
+// ParseCertificateChain parses PEM encoded certificates and returns the information about the first one
+// (the leaf certificate); the rest of the chain is only checked to be parseable.
+func ParseCertificateChain(chainPem string) (*x509.Certificate, *CertificateInfo, error) {
+	var chain []*x509.Certificate
+
+	for rest := []byte(chainPem); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return nil, nil, fmt.Errorf("%w: %q", ErrCertificateUnexpectedPem, block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse certificate #%d: %w", len(chain)+1, err)
+		}
+
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, nil, ErrCertificateMissing
+	}
+
+	leaf := chain[0]
+	info := &CertificateInfo{
+		Subject:           leaf.Subject.String(),
+		CommonName:        leaf.Subject.CommonName,
+		Subjects:          certificateSubjects(leaf),
+		Issuer:            leaf.Issuer.String(),
+		Serial:            leaf.SerialNumber.Text(16),
+		NotBefore:         leaf.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          leaf.NotAfter.UTC().Format(time.RFC3339),
+		Sha256Fingerprint: fingerprint(leaf.Raw),
+		ChainLength:       int64(len(chain)),
+	}
+	info.KeyType, info.KeySize = publicKeyTypeAndSize(leaf.PublicKey)
+
+	return leaf, info, nil
+}
+
+// PrivateKeyMatches reports whether the PEM encoded private key (PKCS #1, PKCS #8 or SEC 1)
+// belongs to the certificate.
+func PrivateKeyMatches(cert *x509.Certificate, privateKeyPem string) (bool, error) {
+	block, _ := pem.Decode([]byte(privateKeyPem))
+	if block == nil {
+		return false, ErrPrivateKeyMissing
+	}
+
+	var privateKey any
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return false, fmt.Errorf("%w: %T", ErrPrivateKeyUnsupported, privateKey)
+	}
+
+	publicKey, ok := signer.Public().(interface{ Equal(x crypto.PublicKey) bool })
+	if !ok {
+		return false, fmt.Errorf("%w: %T", ErrPrivateKeyUnsupported, privateKey)
+	}
+
+	return publicKey.Equal(cert.PublicKey), nil
+}
+
+// certificateSubjects returns the same subjects as the API does: DNS names and IP addresses
+// from the SAN extension or the common name if the certificate doesn't have any.
+func certificateSubjects(cert *x509.Certificate) []string {
+	subjects := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	subjects = append(subjects, cert.DNSNames...)
+
+	for _, ip := range cert.IPAddresses {
+		subjects = append(subjects, ip.String())
+	}
+
+	if len(subjects) == 0 && cert.Subject.CommonName != "" {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+
+	return subjects
+}
+
+func publicKeyTypeAndSize(publicKey any) (string, int64) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", int64(key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA", int64(key.Curve.Params().BitSize)
+	case ed25519.PublicKey:
+		return "Ed25519", ed25519.PublicKeySize * 8
+	default:
+		return "unknown", 0
+	}
+}
+
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	hexBytes := make([]string, len(sum))
+
+	for i, b := range sum {
+		hexBytes[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hexBytes, ":")
+}
@@ -0,0 +1,100 @@
+package ssl
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &CertificateInfoFunction{}
+
+type CertificateInfoFunction struct{}
+
+func NewCertificateInfoFunction() function.Function {
+	return &CertificateInfoFunction{}
+}
+
+func (*CertificateInfoFunction) Metadata(
+	_ context.Context,
+	_ function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "certificate_info"
+}
+
+func (*CertificateInfoFunction) Definition(
+	_ context.Context,
+	_ function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a PEM encoded certificate chain",
+		MarkdownDescription: "Parses a PEM encoded certificate chain offline and returns the information about " +
+			"its first (leaf) certificate. The returned object contains `subject`, `common_name`, `subjects` " +
+			"(DNS names and IP addresses in the same form as `cdn77_ssl.subjects`), `issuer`, `serial` (hex), " +
+			"`not_before` and `not_after` (RFC 3339 timestamps usable with `timecmp()`), `key_type` " +
+			"(`RSA`, `ECDSA` or `Ed25519`), `key_size` (bits), `sha256_fingerprint`, `chain_length` and " +
+			"`key_matches` (whether the private key belongs to the certificate; `null` if no key is given).",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "certificate",
+				MarkdownDescription: "PEM encoded certificate, optionally followed by intermediate certificates",
+			},
+			function.StringParameter{
+				Name:           "private_key",
+				AllowNullValue: true,
+				MarkdownDescription: "PEM encoded private key to check against the certificate; " +
+					"`null` to skip the check",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: certificateInfoAttrTypes()},
+	}
+}
+
+func (*CertificateInfoFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var certificate string
+	var privateKey types.String
+
+	if resp.Error = req.Arguments.Get(ctx, &certificate, &privateKey); resp.Error != nil {
+		return
+	}
+
+	cert, info, err := ParseCertificateChain(certificate)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+
+		return
+	}
+
+	if !privateKey.IsNull() {
+		matches, err := PrivateKeyMatches(cert, privateKey.ValueString())
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(1, err.Error())
+
+			return
+		}
+
+		info.KeyMatches = &matches
+	}
+
+	resp.Error = resp.Result.Set(ctx, info)
+}
+
+func certificateInfoAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"subject":            types.StringType,
+		"common_name":        types.StringType,
+		"subjects":           types.ListType{ElemType: types.StringType},
+		"issuer":             types.StringType,
+		"serial":             types.StringType,
+		"not_before":         types.StringType,
+		"not_after":          types.StringType,
+		"key_type":           types.StringType,
+		"key_size":           types.Int64Type,
+		"sha256_fingerprint": types.StringType,
+		"chain_length":       types.Int64Type,
+		"key_matches":        types.BoolType,
+	}
+}
@@ -0,0 +1,160 @@
+package ssl_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest/testdata"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/ssl"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCertificateInfoFunction(t *testing.T) {
+	otherKey := generateKey(t)
+
+	testCases := []struct {
+		name        string
+		certificate string
+		privateKey  types.String
+		chainLength int64
+		keyMatches  types.Bool
+	}{
+		{
+			name:        "without private key",
+			certificate: testdata.SslCert1,
+			privateKey:  types.StringNull(),
+			chainLength: 1,
+			keyMatches:  types.BoolNull(),
+		},
+		{
+			name:        "matching private key",
+			certificate: testdata.SslCert1,
+			privateKey:  types.StringValue(testdata.SslKey),
+			chainLength: 1,
+			keyMatches:  types.BoolValue(true),
+		},
+		{
+			name:        "other private key",
+			certificate: testdata.SslCert1,
+			privateKey:  types.StringValue(otherKey),
+			chainLength: 1,
+			keyMatches:  types.BoolValue(false),
+		},
+		{
+			name:        "chain",
+			certificate: testdata.SslCert1 + "\n" + testdata.SslCert2,
+			privateKey:  types.StringNull(),
+			chainLength: 2,
+			keyMatches:  types.BoolNull(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := runCertificateInfo(t, tc.certificate, tc.privateKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			attrs := result.Attributes()
+			expected := map[string]attr.Value{
+				"subject":     types.StringValue("CN=example.com"),
+				"common_name": types.StringValue("example.com"),
+				"subjects": types.ListValueMust(types.StringType, []attr.Value{
+					types.StringValue("cdn.example.com"),
+					types.StringValue("other.mycdn.cz"),
+				}),
+				"issuer":     types.StringValue("CN=example.com"),
+				"serial":     types.StringValue("6f4a8f3c2bc791b4611c01daa5d98a0d8bda5ea0"),
+				"not_before": types.StringValue("2024-04-16T12:19:20Z"),
+				"not_after":  types.StringValue("2051-09-02T12:19:20Z"),
+				"key_type":   types.StringValue("ECDSA"),
+				"key_size":   types.Int64Value(256),
+				"sha256_fingerprint": types.StringValue(
+					"F9:25:6A:15:9F:4D:E4:84:CF:35:B5:54:8C:2E:F2:6F:0F:3A:DF:0C:D5:3B:E6:23:35:3B:9B:2D:C0:87:24:97",
+				),
+				"chain_length": types.Int64Value(tc.chainLength),
+				"key_matches":  tc.keyMatches,
+			}
+
+			for name, value := range expected {
+				if !attrs[name].Equal(value) {
+					t.Errorf("%s: expected %s, got %s", name, value, attrs[name])
+				}
+			}
+		})
+	}
+}
+
+func TestCertificateInfoFunction_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		certificate string
+		privateKey  types.String
+	}{
+		{"empty certificate", "", types.StringNull()},
+		{"private key as certificate", testdata.SslKey, types.StringNull()},
+		{"invalid private key", testdata.SslCert1, types.StringValue("not a key")},
+		{"certificate as private key", testdata.SslCert1, types.StringValue(testdata.SslCert2)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := runCertificateInfo(t, tc.certificate, tc.privateKey); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func runCertificateInfo(t *testing.T, certificate string, privateKey types.String) (types.Object, *function.FuncError) {
+	t.Helper()
+
+	var f ssl.CertificateInfoFunction
+
+	definitionResp := function.DefinitionResponse{}
+	f.Definition(t.Context(), function.DefinitionRequest{}, &definitionResp)
+
+	returnType, ok := definitionResp.Definition.Return.GetType().(types.ObjectType)
+	if !ok {
+		t.Fatalf("unexpected return type %T", definitionResp.Definition.Return.GetType())
+	}
+
+	args := function.NewArgumentsData([]attr.Value{types.StringValue(certificate), privateKey})
+	resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(returnType.AttrTypes))}
+
+	f.Run(t.Context(), function.RunRequest{Arguments: args}, &resp)
+
+	if resp.Error != nil {
+		return types.Object{}, resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.Object)
+	if !ok {
+		t.Fatalf("unexpected result type %T", resp.Result.Value())
+	}
+
+	return result, nil
+}
+
+func generateKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
@@ -13,6 +13,20 @@ import (
 
 type Reader struct{}
 
+func NewDataSourceReader() util.Reader {
+	toModel := func(data DataSourceModel) Model {
+		return Model{BaseModel: data.BaseModel}
+	}
+	fromModel := func(model Model) DataSourceModel {
+		return DataSourceModel{
+			BaseModel:  model.BaseModel,
+			PrivateKey: model.PrivateKey,
+		}
+	}
+
+	return util.NewDataSourceReader(&Reader{}, toModel, fromModel)
+}
+
 func (*Reader) ErrMessage() string {
 	return "Failed to fetch SSL"
 }
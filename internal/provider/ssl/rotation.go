@@ -0,0 +1,111 @@
+package ssl
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithConfigure   = &RotationResource{}
+	_ resource.ResourceWithImportState = &RotationResource{}
+)
+
+type RotationResource struct {
+	*util.BaseResource
+}
+
+func (*RotationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// There is no previous certificate yet, so there are no CDNs to switch.
+	var data RotationModel
+
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.SslId
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+func (r *RotationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	diags := &resp.Diagnostics
+	var data RotationModel
+
+	if diags.Append(req.State.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	const errMessage = "Failed to fetch SSL"
+
+	response, err := r.Client.SslSniDetailWithResponse(ctx, data.SslId.ValueString())
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(*cdn77.Ssl) {
+		diags.Append(resp.State.Set(ctx, data)...)
+	})
+}
+
+func (r *RotationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	diags := &resp.Diagnostics
+	var data, state RotationModel
+
+	if diags.Append(req.Plan.Get(ctx, &data)...); diags.HasError() {
+		return
+	}
+
+	if diags.Append(req.State.Get(ctx, &state)...); diags.HasError() {
+		return
+	}
+
+	const errMessage = "Failed to switch CDNs to SSL certificate"
+
+	if !data.SslId.Equal(state.SslId) {
+		cdns, ok := fetchAssignedCdns(ctx, r.Client, diags, errMessage, state.SslId.ValueString())
+		if !ok {
+			return
+		}
+
+		const objectName, replacementAttr = "SSL certificate", "ssl_id"
+		request := cdn77.CdnEditJSONRequestBody{
+			Ssl: &cdn77.CdnSsl{Type: cdn77.SNI, SslId: data.SslId.ValueStringPointer()},
+		}
+
+		if !util.DetachCdns(ctx, r.Client, diags, errMessage, objectName, cdns, replacementAttr, data.SslId, request) {
+			return
+		}
+	}
+
+	data.Id = data.SslId
+
+	diags.Append(resp.State.Set(ctx, data)...)
+}
+
+func (*RotationResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+	// The CDNs keep using the current certificate; only the rotation is removed from the state.
+}
+
+func (*RotationResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	diags := &resp.Diagnostics
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue(req.ID))...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("ssl_id"), types.StringValue(req.ID))...)
+}
@@ -0,0 +1,33 @@
+package ssl
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RotationModel struct {
+	Id    types.String `tfsdk:"id"`
+	SslId types.String `tfsdk:"ssl_id"`
+}
+
+func CreateRotationResourceSchema() schema.Schema {
+	return schema.Schema{
+		Description: "SSL rotation resource switches all CDNs using the previous SSL certificate to the current one " +
+			`whenever "ssl_id" changes. Reference a "cdn77_ssl" resource with "replace_on_change" enabled and the ` +
+			`"create_before_destroy" lifecycle option, so the CDNs are switched after the new certificate is ` +
+			"uploaded and before the old one is deleted.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: `ID of the rotation; it's the same as "ssl_id"`,
+			},
+			"ssl_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID (UUID) of the SSL certificate the CDNs are switched to",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+		},
+	}
+}
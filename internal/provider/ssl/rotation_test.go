@@ -0,0 +1,180 @@
+package ssl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
+	"github.com/cdn77/terraform-provider-cdn77/internal/acctest/testdata"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/origin"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/ssl"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestResource_ModifyPlanReplaceOnChange(t *testing.T) {
+	s := ssl.CreateResourceSchema()
+	nullRaw := tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)
+
+	testCases := map[string]struct {
+		replaceOnChange bool
+		certificate     string
+		expected        []path.Path
+	}{
+		"changed certificate is replaced": {
+			replaceOnChange: true,
+			certificate:     "new",
+			expected:        []path.Path{path.Root("certificate")},
+		},
+		"unchanged certificate is kept": {
+			replaceOnChange: true,
+			certificate:     "old",
+		},
+		"changed certificate is updated in place by default": {
+			certificate: "new",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			state := tfsdk.State{Schema: s, Raw: nullRaw}
+			plan := tfsdk.Plan{Schema: s, Raw: nullRaw}
+
+			diags := state.SetAttribute(t.Context(), path.Root("certificate"), types.StringValue("old"))
+			diags.Append(state.SetAttribute(t.Context(), path.Root("private_key"), types.StringValue("key"))...)
+			diags.Append(plan.SetAttribute(t.Context(), path.Root("certificate"), types.StringValue(tc.certificate))...)
+			diags.Append(plan.SetAttribute(t.Context(), path.Root("private_key"), types.StringValue("key"))...)
+			diags.Append(
+				plan.SetAttribute(t.Context(), path.Root("replace_on_change"), types.BoolValue(tc.replaceOnChange))...,
+			)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			resp := fwresource.ModifyPlanResponse{Plan: plan}
+			(&ssl.Resource{}).ModifyPlan(t.Context(), fwresource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			if fmt.Sprint(resp.RequiresReplace) != fmt.Sprint(tc.expected) {
+				t.Errorf("expected %v to require replacement, got %v", tc.expected, resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestAccSslRotationResource(t *testing.T) {
+	const rsc = "cdn77_ssl_rotation.rotation"
+	client := acctest.GetClient(t)
+	var sslId, newSslId string
+
+	originRequest := cdn77.OriginCreateUrlJSONRequestBody{
+		Label:  "random origin",
+		Scheme: "https",
+		Host:   "my-totally-random-custom-host.com",
+	}
+	originResponse, err := client.OriginCreateUrlWithResponse(t.Context(), originRequest)
+	acctest.AssertResponseOk(t, "Failed to create Origin: %s", originResponse, err)
+
+	originId := originResponse.JSON201.Id
+
+	t.Cleanup(func() {
+		acctest.MustDeleteOrigin(t, client, origin.TypeUrl, originId)
+	})
+
+	cdnRequest := cdn77.CdnAddJSONRequestBody{Label: "some cdn", OriginId: originId}
+	cdnResponse, err := client.CdnAddWithResponse(t.Context(), cdnRequest)
+	acctest.AssertResponseOk(t, "Failed to create CDN: %s", cdnResponse, err)
+
+	cdnId := cdnResponse.JSON201.Id
+
+	t.Cleanup(func() {
+		acctest.MustDeleteCdn(t, client, cdnId)
+	})
+
+	editCdnSsl := func(cdnSsl cdn77.CdnSsl) {
+		response, err := client.CdnEditWithResponse(t.Context(), cdnId, cdn77.CdnEditJSONRequestBody{Ssl: &cdnSsl})
+		acctest.AssertResponseOk(t, "Failed to edit CDN: %s", response, err)
+	}
+
+	acctest.Run(t, checkSslsDestroyed(client),
+		resource.TestStep{
+			Config: acctest.Config(rotationConfig, "cert", testdata.SslCert1, "key", testdata.SslKey),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr("cdn77_ssl.crt", "id", &sslId),
+				acctest.CheckAttr(rsc, "id", &sslId),
+				acctest.CheckAttr(rsc, "ssl_id", &sslId),
+			),
+		},
+		resource.TestStep{
+			PreConfig: func() {
+				editCdnSsl(cdn77.CdnSsl{Type: cdn77.SNI, SslId: &sslId})
+			},
+			Config: acctest.Config(rotationConfig, "cert", testdata.SslCert2, "key", testdata.SslKey),
+			ConfigPlanChecks: resource.ConfigPlanChecks{
+				PreApply: []plancheck.PlanCheck{
+					plancheck.ExpectResourceAction("cdn77_ssl.crt", plancheck.ResourceActionCreateBeforeDestroy),
+					plancheck.ExpectResourceAction(rsc, plancheck.ResourceActionUpdate),
+				},
+			},
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr("cdn77_ssl.crt", "id", &newSslId),
+				acctest.CheckAttr(rsc, "ssl_id", &newSslId),
+				func(*terraform.State) error {
+					if newSslId == sslId {
+						return fmt.Errorf("expected SSL certificate %s to be replaced", sslId)
+					}
+
+					response, err := client.CdnDetailWithResponse(t.Context(), cdnId)
+					if err = acctest.CheckResponse("failed to get CDN: %s", response, err); err != nil {
+						return err
+					}
+
+					return acctest.EqualField("ssl.ssl_id", *response.JSON200.Ssl.SslId, newSslId)
+				},
+			),
+		},
+		resource.TestStep{
+			PreConfig: func() {
+				// The CDN isn't managed by Terraform, so it must stop using the certificate before it's destroyed.
+				editCdnSsl(cdn77.CdnSsl{Type: cdn77.InstantSsl})
+			},
+			Config:           acctest.Config(rotationConfig, "cert", testdata.SslCert2, "key", testdata.SslKey),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionNoop),
+		},
+	)
+}
+
+const rotationConfig = `
+resource "cdn77_ssl" "crt" {
+	certificate = trimspace(
+	<<EOT
+		{cert}
+	EOT
+	)
+	private_key = trimspace(
+	<<EOT
+		{key}
+	EOT
+	)
+	replace_on_change = true
+
+	lifecycle {
+		create_before_destroy = true
+	}
+}
+
+resource "cdn77_ssl_rotation" "rotation" {
+	ssl_id = cdn77_ssl.crt.id
+}
+`
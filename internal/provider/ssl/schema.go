@@ -1,6 +1,10 @@
 package ssl
 
 import (
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -11,6 +15,18 @@ import (
 type Model struct {
 	BaseModel
 
+	PrivateKey          types.String `tfsdk:"private_key"`
+	PrivateKeyWo        types.String `tfsdk:"private_key_wo"`
+	PrivateKeyWoVersion types.Int64  `tfsdk:"private_key_wo_version"`
+	DeletionProtection  types.Bool   `tfsdk:"deletion_protection"`
+	ForceDetachSslId    types.String `tfsdk:"force_detach_ssl_id"`
+	ReplaceOnChange     types.Bool   `tfsdk:"replace_on_change"`
+}
+
+// DataSourceModel leaves out the resource-only attributes (see ResourceOnlyAttrs).
+type DataSourceModel struct {
+	BaseModel
+
 	PrivateKey types.String `tfsdk:"private_key"`
 }
 
@@ -21,18 +37,60 @@ type BaseModel struct {
 	ExpiresAt   types.String `tfsdk:"expires_at"`
 }
 
+// ResourceOnlyAttrs are left out from the data source schema.
+var ResourceOnlyAttrs = []string{
+	"private_key_wo",
+	"private_key_wo_version",
+	"deletion_protection",
+	"force_detach_ssl_id",
+	"replace_on_change",
+}
+
 func CreateResourceSchema() schema.Schema {
+	privateKeyValidator := stringvalidator.ExactlyOneOf(path.MatchRoot("private_key"), path.MatchRoot("private_key_wo"))
+
 	s := CreateBaseResourceSchema()
 	s.Attributes["private_key"] = schema.StringAttribute{
 		Description: "Private key associated with the certificate. " +
 			"Must not contain leading or trailing whitespace. " +
 			"If loading from a file, use trimspace(file(...)) or chomp(file(...)) to remove extra newlines.",
-		Required:  true,
+		Optional:  true,
 		Sensitive: true,
 		Validators: []validator.String{
 			NoLeadingTrailingWhitespace(),
+			privateKeyValidator,
 		},
 	}
+	s.Attributes["private_key_wo"] = schema.StringAttribute{
+		Description: `Write-only alternative to "private_key" which is never stored in the state ` +
+			"(requires Terraform 1.11 or later). Change \"private_key_wo_version\" to update the key.",
+		Optional:  true,
+		WriteOnly: true,
+		Validators: []validator.String{
+			NoLeadingTrailingWhitespace(),
+			privateKeyValidator,
+			stringvalidator.AlsoRequires(path.MatchRoot("private_key_wo_version")),
+		},
+	}
+	s.Attributes["private_key_wo_version"] = schema.Int64Attribute{
+		Description: `Version of "private_key_wo"; change it whenever the write-only private key changes`,
+		Optional:    true,
+		Validators: []validator.Int64{
+			int64validator.AlsoRequires(path.MatchRoot("private_key_wo")),
+		},
+	}
+	s.Attributes["deletion_protection"] = util.DeletionProtectionSchemaAttr()
+	s.Attributes["force_detach_ssl_id"] = schema.StringAttribute{
+		Description: "ID of the SSL certificate which CDNs using this certificate are switched to before this " +
+			"certificate is deleted. Without it, the deletion fails if any CDN uses this certificate.",
+		Optional: true,
+	}
+	s.Attributes["replace_on_change"] = schema.BoolAttribute{
+		Description: "If true, a change of the certificate or the private key uploads a new SSL certificate instead " +
+			`of updating this one in place. Combine it with the "create_before_destroy" lifecycle option and the ` +
+			`"cdn77_ssl_rotation" resource to switch the CDNs to the new certificate before this one is deleted.`,
+		Optional: true,
+	}
 
 	return s
 }
@@ -4,25 +4,62 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
 	_ resource.ResourceWithConfigure   = &Resource{}
 	_ resource.ResourceWithImportState = &Resource{}
+	_ resource.ResourceWithModifyPlan  = &Resource{}
 )
 
 type Resource struct {
 	*util.BaseResource
 }
 
+// ModifyPlan replaces the certificate instead of updating it in place when "replace_on_change" is enabled.
+func (*Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	diags := &resp.Diagnostics
+	var replaceOnChange types.Bool
+
+	if diags.Append(req.Plan.GetAttribute(ctx, path.Root("replace_on_change"), &replaceOnChange)...); diags.HasError() {
+		return
+	}
+
+	if !replaceOnChange.ValueBool() {
+		return
+	}
+
+	// The write-only private key isn't available in the plan, its version is compared instead.
+	paths := []path.Path{path.Root("certificate"), path.Root("private_key"), path.Root("private_key_wo_version")}
+
+	for _, p := range paths {
+		var planValue, stateValue attr.Value
+
+		diags.Append(req.Plan.GetAttribute(ctx, p, &planValue)...)
+		diags.Append(req.State.GetAttribute(ctx, p, &stateValue)...)
+
+		if !diags.HasError() && !planValue.Equal(stateValue) {
+			resp.RequiresReplace = append(resp.RequiresReplace, p)
+		}
+	}
+}
+
 func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	diags := &resp.Diagnostics
 	var data Model
@@ -31,9 +68,14 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	privateKey, ok := getPrivateKey(ctx, diags, req.Config, data)
+	if !ok {
+		return
+	}
+
 	request := cdn77.SslSniAddJSONRequestBody{
 		Certificate: data.Certificate.ValueString(),
-		PrivateKey:  data.PrivateKey.ValueString(),
+		PrivateKey:  privateKey,
 	}
 
 	const errMessage = "Failed to create SSL"
@@ -63,9 +105,14 @@ func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp
 		return
 	}
 
+	privateKey, ok := getPrivateKey(ctx, diags, req.Config, data)
+	if !ok {
+		return
+	}
+
 	request := cdn77.SslSniEditJSONRequestBody{
 		Certificate: data.Certificate.ValueString(),
-		PrivateKey:  data.PrivateKey.ValueStringPointer(),
+		PrivateKey:  &privateKey,
 	}
 
 	const errMessage = "Failed to update SSL"
@@ -96,6 +143,14 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 
 	const errMessage = "Failed to delete SSL"
 
+	if util.IsDeletionProtected(diags, data.DeletionProtection, errMessage) {
+		return
+	}
+
+	if !r.detachCdns(ctx, diags, errMessage, data) {
+		return
+	}
+
 	response, err := r.Client.SslSniDeleteWithResponse(ctx, data.Id.ValueString())
 	if err != nil {
 		diags.AddError(errMessage, err.Error())
@@ -113,11 +168,18 @@ func (*Resource) ImportState(
 ) {
 	idParts := strings.Split(req.ID, ",")
 
+	// The private key can be omitted when it's managed by the write-only "private_key_wo" attribute.
+	if len(idParts) == 1 && idParts[0] != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[0])...)
+
+		return
+	}
+
 	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 		resp.Diagnostics.AddError(
 			"Unexpected Import Identifier",
 			fmt.Sprintf(
-				"Expected import identifier with format: <id>,<privateKey>. "+
+				"Expected import identifier with format: <id>,<privateKey> or <id> (when using private_key_wo). "+
 					"<privateKey> must be the whole PEM file (including headers) encoded via base64. Got: %q",
 				req.ID,
 			),
@@ -143,8 +205,69 @@ func (*Resource) ImportState(
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("private_key"), trimmedKey)...)
 }
 
+// getPrivateKey returns the private key from either the "private_key" attribute or the write-only
+// "private_key_wo" attribute, which is available only in the configuration.
+func getPrivateKey(ctx context.Context, diags *diag.Diagnostics, config tfsdk.Config, data Model) (string, bool) {
+	if !data.PrivateKey.IsNull() {
+		return data.PrivateKey.ValueString(), true
+	}
+
+	var privateKeyWo types.String
+	if diags.Append(config.GetAttribute(ctx, path.Root("private_key_wo"), &privateKeyWo)...); diags.HasError() {
+		return "", false
+	}
+
+	return privateKeyWo.ValueString(), true
+}
+
 var _ datasource.DataSourceWithConfigure = &DataSource{}
 
 type DataSource struct {
 	*util.BaseDataSource
 }
+
+// detachCdns makes sure no CDN uses the certificate before it's deleted; see util.DetachCdns.
+func (r *Resource) detachCdns(ctx context.Context, diags *diag.Diagnostics, errMessage string, data Model) bool {
+	cdns, ok := fetchAssignedCdns(ctx, r.Client, diags, errMessage, data.Id.ValueString())
+	if !ok {
+		return false
+	}
+
+	const objectName, replacementAttr = "SSL certificate", "force_detach_ssl_id"
+	replacementId := data.ForceDetachSslId
+	request := cdn77.CdnEditJSONRequestBody{
+		Ssl: &cdn77.CdnSsl{Type: cdn77.SNI, SslId: replacementId.ValueStringPointer()},
+	}
+
+	return util.DetachCdns(ctx, r.Client, diags, errMessage, objectName, cdns, replacementAttr, replacementId, request)
+}
+
+// fetchAssignedCdns returns the CDNs using the certificate; there are none if the certificate doesn't exist.
+func fetchAssignedCdns(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	errMessage string,
+	sslId string,
+) ([]util.CdnReference, bool) {
+	response, err := client.SslSniDetailWithResponse(ctx, sslId)
+	if err != nil {
+		diags.AddError(errMessage, err.Error())
+
+		return nil, false
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		return nil, true
+	}
+
+	var cdns []util.CdnReference
+
+	util.ProcessResponse(diags, response, errMessage, response.JSON200, func(detail *cdn77.Ssl) {
+		for _, c := range detail.AssignedResources {
+			cdns = append(cdns, util.CdnReference{Id: c.Id, Label: c.Label})
+		}
+	})
+
+	return cdns, !diags.HasError()
+}
@@ -5,15 +5,25 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cdn77/cdn77-client-go/v2"
 	"github.com/cdn77/terraform-provider-cdn77/internal/acctest"
 	"github.com/cdn77/terraform-provider-cdn77/internal/acctest/testdata"
+	"github.com/cdn77/terraform-provider-cdn77/internal/provider/ssl"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -99,6 +109,54 @@ func TestAccSslResource_Import(t *testing.T) {
 	)
 }
 
+func TestAccSslResource_WriteOnlyPrivateKey(t *testing.T) {
+	const rsc = "cdn77_ssl.crt"
+	client := acctest.GetClient(t)
+	var sslId string
+
+	acctest.RunWriteOnly(t, checkSslsDestroyed(client),
+		resource.TestStep{
+			Config: acctest.Config(
+				resourceWriteOnlyConfig, "cert", testdata.SslCert1, "key", testdata.SslKey, "version", 1,
+			),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionCreate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAndAssignAttr(rsc, "id", &sslId),
+				resource.TestCheckResourceAttr(rsc, "certificate", testdata.SslCert1),
+				resource.TestCheckResourceAttr(rsc, "private_key_wo_version", "1"),
+				resource.TestCheckNoResourceAttr(rsc, "private_key"),
+				resource.TestCheckNoResourceAttr(rsc, "private_key_wo"),
+			),
+		},
+		resource.TestStep{
+			Config: acctest.Config(
+				resourceWriteOnlyConfig, "cert", testdata.SslCert2, "key", testdata.SslKey, "version", 2,
+			),
+			ConfigPlanChecks: acctest.ConfigPlanChecks(rsc, plancheck.ResourceActionUpdate),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				acctest.CheckAttr(rsc, "id", &sslId),
+				resource.TestCheckResourceAttr(rsc, "certificate", testdata.SslCert2),
+				resource.TestCheckResourceAttr(rsc, "private_key_wo_version", "2"),
+				resource.TestCheckNoResourceAttr(rsc, "private_key"),
+				resource.TestCheckNoResourceAttr(rsc, "private_key_wo"),
+
+				checkSsl(client, &sslId, func(o *cdn77.Ssl) error {
+					return acctest.EqualField("certificate", o.Certificate, testdata.SslCert2)
+				}),
+			),
+		},
+		resource.TestStep{
+			ResourceName:            rsc,
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"private_key_wo_version"},
+			ImportStateIdFunc: func(*terraform.State) (string, error) {
+				return sslId, nil
+			},
+		},
+	)
+}
+
 func TestAccSslResource_WhitespaceHandling(t *testing.T) {
 	const rsc = "cdn77_ssl.crt"
 	client := acctest.GetClient(t)
@@ -243,8 +301,161 @@ resource "cdn77_ssl" "crt" {
 }
 `
 
+const resourceWriteOnlyConfig = `
+resource "cdn77_ssl" "crt" {
+	certificate = trimspace(
+	<<EOT
+		{cert}
+	EOT
+	)
+	private_key_wo = trimspace(
+	<<EOT
+		{key}
+	EOT
+	)
+	private_key_wo_version = {version}
+}
+`
+
 const dataSourceConfig = `
 data "cdn77_ssl" "ipsum" {
   id = "{id}"
 }
 `
+
+func TestResource_DeleteDetachesCdns(t *testing.T) {
+	const sslId = "ssl-id"
+
+	assignedCdns := []cdn77.SslCdn{{Id: 1, Label: "first"}, {Id: 2, Label: "second"}}
+
+	testCases := map[string]struct {
+		assignedCdns     []cdn77.SslCdn
+		forceDetachSslId types.String
+		sslMissing       bool
+		expectedEdits    []int
+		expectedDeleted  bool
+		expectedError    string
+	}{
+		"refused while used by CDNs": {
+			assignedCdns:     assignedCdns,
+			forceDetachSslId: types.StringNull(),
+			expectedError:    "used by the following CDNs: 1 (first), 2 (second)",
+		},
+		"CDNs detached to replacement": {
+			assignedCdns:     assignedCdns,
+			forceDetachSslId: types.StringValue("replacement-id"),
+			expectedEdits:    []int{1, 2},
+			expectedDeleted:  true,
+		},
+		"certificate already deleted": {
+			forceDetachSslId: types.StringNull(),
+			sslMissing:       true,
+			expectedDeleted:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := &sslClient{assignedCdns: tc.assignedCdns, sslMissing: tc.sslMissing}
+			r := &ssl.Resource{BaseResource: util.NewBaseResource("ssl", ssl.CreateResourceSchema, nil)}
+			r.Client = client
+
+			s := ssl.CreateResourceSchema()
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(t.Context()), nil)}
+			diags := state.SetAttribute(t.Context(), path.Root("id"), types.StringValue(sslId))
+			diags.Append(state.SetAttribute(t.Context(), path.Root("force_detach_ssl_id"), tc.forceDetachSslId)...)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			var resp fwresource.DeleteResponse
+			r.Delete(t.Context(), fwresource.DeleteRequest{State: state}, &resp)
+
+			if !slices.Equal(client.edits, tc.expectedEdits) {
+				t.Errorf("expected edited CDNs %v, got %v", tc.expectedEdits, client.edits)
+			}
+
+			for _, id := range client.sslIds {
+				if id != tc.forceDetachSslId.ValueString() {
+					t.Errorf("expected CDNs to be switched to %s, got %s", tc.forceDetachSslId, id)
+				}
+			}
+
+			if client.deleted != tc.expectedDeleted {
+				t.Errorf("expected the SSL to be deleted: %t, got %t", tc.expectedDeleted, client.deleted)
+			}
+
+			if tc.expectedError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("unexpected error: %v", resp.Diagnostics)
+				}
+
+				return
+			}
+
+			if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), tc.expectedError) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// sslClient serves a certificate assigned to the given CDNs, records the CDN edits and deletes the certificate; both
+// the detail and the deletion respond with 404 if sslMissing is set.
+type sslClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	assignedCdns []cdn77.SslCdn
+	sslMissing   bool
+	edits        []int
+	sslIds       []string
+	deleted      bool
+}
+
+func (c *sslClient) SslSniDetailWithResponse(
+	_ context.Context,
+	id string,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.SslSniDetailResponse, error) {
+	if c.sslMissing {
+		return &cdn77.SslSniDetailResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+			JSON404:      &cdn77.Errors{Errors: []string{"SSL not found"}},
+		}, nil
+	}
+
+	return &cdn77.SslSniDetailResponse{
+		HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+		JSON200:      &cdn77.Ssl{Id: id, AssignedResources: c.assignedCdns},
+	}, nil
+}
+
+func (c *sslClient) CdnEditWithResponse(
+	_ context.Context,
+	id int,
+	body cdn77.CdnEditJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnEditResponse, error) {
+	c.edits = append(c.edits, id)
+	c.sslIds = append(c.sslIds, *body.Ssl.SslId)
+
+	return &cdn77.CdnEditResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+func (c *sslClient) SslSniDeleteWithResponse(
+	context.Context,
+	string,
+	...cdn77.RequestEditorFn,
+) (*cdn77.SslSniDeleteResponse, error) {
+	c.deleted = true
+
+	if c.sslMissing {
+		return &cdn77.SslSniDeleteResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+			JSON404:      &cdn77.Errors{Errors: []string{"SSL not found"}},
+		}, nil
+	}
+
+	return &cdn77.SslSniDeleteResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
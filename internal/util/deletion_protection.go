@@ -0,0 +1,32 @@
+package util
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func DeletionProtectionSchemaAttr() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional: true,
+		Description: "If true, the resource can't be deleted (neither when it's removed from the configuration nor " +
+			`when it's replaced); set it to false and apply the change before deleting the resource`,
+	}
+}
+
+// IsDeletionProtected adds an error if the deletion protection of the resource is enabled.
+func IsDeletionProtected(diags *diag.Diagnostics, deletionProtection types.Bool, errMessage string) bool {
+	if !deletionProtection.ValueBool() {
+		return false
+	}
+
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		errMessage,
+		`Deletion protection is enabled; set "deletion_protection" to false and apply the change before deleting `+
+			"the resource",
+	)
+
+	return true
+}
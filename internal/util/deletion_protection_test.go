@@ -0,0 +1,49 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIsDeletionProtected(t *testing.T) {
+	testCases := map[string]struct {
+		deletionProtection types.Bool
+		expected           bool
+	}{
+		"null":     {deletionProtection: types.BoolNull()},
+		"disabled": {deletionProtection: types.BoolValue(false)},
+		"enabled":  {deletionProtection: types.BoolValue(true), expected: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+
+			isProtected := util.IsDeletionProtected(&diags, tc.deletionProtection, "Failed to delete")
+			if isProtected != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, isProtected)
+			}
+
+			if !tc.expected {
+				if diags.HasError() {
+					t.Errorf("unexpected error: %v", diags)
+				}
+
+				return
+			}
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected an error, got %v", diags)
+			}
+
+			d, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !d.Path().Equal(path.Root("deletion_protection")) {
+				t.Errorf("expected the error to point at deletion_protection, got %v", diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// CdnReference identifies a CDN which uses an object that is being deleted.
+type CdnReference struct {
+	Id    int
+	Label string
+}
+
+// DetachCdns makes sure no CDN uses the object that is being deleted. Without a replacement, an error naming the CDNs
+// is added; otherwise the CDNs are switched to the replacement using the given edit request.
+func DetachCdns(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	diags *diag.Diagnostics,
+	errMessage string,
+	objectName string,
+	cdns []CdnReference,
+	replacementAttr string,
+	replacementId types.String,
+	request cdn77.CdnEditJSONRequestBody,
+) bool {
+	if len(cdns) == 0 {
+		return true
+	}
+
+	if replacementId.IsNull() {
+		names := make([]string, 0, len(cdns))
+		for _, c := range cdns {
+			names = append(names, fmt.Sprintf("%d (%s)", c.Id, c.Label))
+		}
+
+		diags.AddError(errMessage, fmt.Sprintf(
+			"The %s is used by the following CDNs: %s. Switch them to another %s first or set %q.",
+			objectName,
+			strings.Join(names, ", "),
+			objectName,
+			replacementAttr,
+		))
+
+		return false
+	}
+
+	for _, c := range cdns {
+		response, err := client.CdnEditWithResponse(ctx, c.Id, request)
+		if err != nil {
+			diags.AddError(errMessage, fmt.Sprintf("Failed to detach CDN %d: %s", c.Id, err.Error()))
+
+			return false
+		}
+
+		ProcessEmptyResponse(diags, response, fmt.Sprintf("%s: failed to detach CDN %d", errMessage, c.Id), func() {})
+
+		if diags.HasError() {
+			return false
+		}
+	}
+
+	return true
+}
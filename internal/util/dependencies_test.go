@@ -0,0 +1,125 @@
+package util_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDetachCdns(t *testing.T) {
+	cdns := []util.CdnReference{{Id: 1, Label: "first"}, {Id: 2, Label: "second"}}
+
+	testCases := map[string]struct {
+		cdns          []util.CdnReference
+		replacementId types.String
+		failedEditId  int
+		expectedEdits []int
+		expectedError string
+	}{
+		"no CDNs": {
+			replacementId: types.StringNull(),
+		},
+		"refused without replacement": {
+			cdns:          cdns,
+			replacementId: types.StringNull(),
+			expectedError: `1 (first), 2 (second). Switch them to another Origin first or set "force_detach_origin_id"`,
+		},
+		"detached to replacement": {
+			cdns:          cdns,
+			replacementId: types.StringValue("replacement"),
+			expectedEdits: []int{1, 2},
+		},
+		"failed detach": {
+			cdns:          cdns,
+			replacementId: types.StringValue("replacement"),
+			failedEditId:  1,
+			expectedEdits: []int{1},
+			expectedError: "failed to detach CDN 1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+
+			client := &detachClient{failedEditId: tc.failedEditId}
+			request := cdn77.CdnEditJSONRequestBody{OriginId: tc.replacementId.ValueStringPointer()}
+			ok := util.DetachCdns(
+				t.Context(),
+				client,
+				&diags,
+				"Failed to delete Origin",
+				"Origin",
+				tc.cdns,
+				"force_detach_origin_id",
+				tc.replacementId,
+				request,
+			)
+
+			if ok != (tc.expectedError == "") {
+				t.Errorf("expected success to be %t, got %t", tc.expectedError == "", ok)
+			}
+
+			if !slices.Equal(client.edits, tc.expectedEdits) {
+				t.Errorf("expected edited CDNs %v, got %v", tc.expectedEdits, client.edits)
+			}
+
+			for _, originId := range client.originIds {
+				if originId != tc.replacementId.ValueString() {
+					t.Errorf("expected CDNs to be switched to %s, got %s", tc.replacementId, originId)
+				}
+			}
+
+			if tc.expectedError == "" {
+				if diags.HasError() {
+					t.Errorf("unexpected error: %v", diags)
+				}
+
+				return
+			}
+
+			if diags.ErrorsCount() != 1 || !strings.Contains(diagText(diags.Errors()[0]), tc.expectedError) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedError, diags)
+			}
+		})
+	}
+}
+
+// detachClient records the edited CDNs; the edit of failedEditId fails.
+type detachClient struct {
+	cdn77.ClientWithResponsesInterface
+
+	failedEditId int
+	edits        []int
+	originIds    []string
+}
+
+func (c *detachClient) CdnEditWithResponse(
+	_ context.Context,
+	id int,
+	body cdn77.CdnEditJSONRequestBody,
+	_ ...cdn77.RequestEditorFn,
+) (*cdn77.CdnEditResponse, error) {
+	c.edits = append(c.edits, id)
+	c.originIds = append(c.originIds, *body.OriginId)
+
+	if id == c.failedEditId {
+		return &cdn77.CdnEditResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusUnprocessableEntity},
+			JSON422:      &cdn77.FieldErrors{Errors: []string{"invalid origin"}},
+		}, nil
+	}
+
+	return &cdn77.CdnEditResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNoContent}}, nil
+}
+
+func diagText(d diag.Diagnostic) string {
+	return d.Summary() + ": " + d.Detail()
+}
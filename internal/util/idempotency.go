@@ -0,0 +1,140 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/oapi-codegen/nullable"
+)
+
+// IdempotencyMarker is a random token added to the note of a newly created object, so the object can be found (and
+// adopted) if the create response is lost. The markers are left out of the notes read from the API (see
+// WithoutIdempotencyMarkers), so the marker doesn't have to be removed by an additional request.
+type IdempotencyMarker string
+
+// lostResponseTimeout limits the handling of a lost response when the original context has already expired.
+const lostResponseTimeout = time.Minute
+
+var idempotencyMarkerRegexp = regexp.MustCompile(` ?\[terraform-create:[0-9a-f]{16}]`)
+
+func NewIdempotencyMarker() (IdempotencyMarker, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency marker: %w", err)
+	}
+
+	return IdempotencyMarker("[terraform-create:" + hex.EncodeToString(token) + "]"), nil
+}
+
+// Mark returns the note with the marker appended.
+func (m IdempotencyMarker) Mark(note nullable.Nullable[string]) nullable.Nullable[string] {
+	if note.IsNull() || !note.IsSpecified() {
+		return nullable.NewNullableWithValue(string(m))
+	}
+
+	return nullable.NewNullableWithValue(note.MustGet() + " " + string(m))
+}
+
+func (m IdempotencyMarker) IsIn(note nullable.Nullable[string]) bool {
+	return note.IsSpecified() && !note.IsNull() && strings.Contains(note.MustGet(), string(m))
+}
+
+// WithoutIdempotencyMarkers returns the note as it was before it was marked.
+func WithoutIdempotencyMarkers(note nullable.Nullable[string]) nullable.Nullable[string] {
+	if note.IsNull() || !note.IsSpecified() {
+		return note
+	}
+
+	value := note.MustGet()
+	stripped := idempotencyMarkerRegexp.ReplaceAllString(value, "")
+
+	// A null note is marked by the marker alone, while a marker appended to an empty note is preceded by a space.
+	if stripped == "" && value != "" && !strings.HasPrefix(value, " ") {
+		return nullable.NewNullNullable[string]()
+	}
+
+	return nullable.NewNullableWithValue(stripped)
+}
+
+// LostResponseContext returns the context for looking up (and saving) the object created by a request whose response
+// was lost. If the request failed because the context expired, the returned context has a new deadline.
+func LostResponseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(context.WithoutCancel(ctx), lostResponseTimeout)
+}
+
+// IsLostResponse reports whether a create request could have created the object even though its response wasn't
+// received, i.e. the request timed out or the API responded with a server error.
+func IsLostResponse(response Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+
+		return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+	}
+
+	return response.StatusCode() >= http.StatusInternalServerError
+}
+
+// FindMarked returns the only item with the marker in its note; the result is nil if there is no such item. An error is
+// added if more items have the marker, as it isn't clear which one was created by the lost request.
+func FindMarked[T any](
+	diags *diag.Diagnostics,
+	objectName string,
+	marker IdempotencyMarker,
+	items []T,
+	idAndNote func(item T) (string, nullable.Nullable[string]),
+) *T {
+	var found []int
+	var ids []string
+
+	for i, item := range items {
+		if id, note := idAndNote(item); marker.IsIn(note) {
+			found = append(found, i)
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil
+	case 1:
+		diags.AddWarning(
+			fmt.Sprintf("Adopted %s created by a lost request", objectName),
+			fmt.Sprintf(
+				"The response of the create request was lost, but %s %s with the marker %q in its note was found "+
+					"and saved to the state instead of creating a duplicate",
+				objectName,
+				ids[0],
+				marker,
+			),
+		)
+
+		return &items[found[0]]
+	default:
+		diags.AddError(
+			fmt.Sprintf("Ambiguous adoption of %s created by a lost request", objectName),
+			fmt.Sprintf(
+				"The response of the create request was lost and %d objects (%s) have the marker %q in their note, "+
+					"so it isn't clear which one to adopt. Import the right %s and delete the others.",
+				len(found),
+				strings.Join(ids, ", "),
+				marker,
+				objectName,
+			),
+		)
+
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cdn77/cdn77-client-go/v2"
+	"github.com/cdn77/terraform-provider-cdn77/internal/util"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/oapi-codegen/nullable"
+)
+
+const testMarker = util.IdempotencyMarker("[terraform-create:0123456789abcdef]")
+
+func TestIdempotencyMarker_Mark(t *testing.T) {
+	testCases := map[string]struct {
+		note             nullable.Nullable[string]
+		expected         string
+		expectedStripped nullable.Nullable[string]
+	}{
+		"null note": {
+			note:             nullable.NewNullNullable[string](),
+			expected:         "[terraform-create:0123456789abcdef]",
+			expectedStripped: nullable.NewNullNullable[string](),
+		},
+		"unspecified note": {
+			note:             nullable.Nullable[string]{},
+			expected:         "[terraform-create:0123456789abcdef]",
+			expectedStripped: nullable.NewNullNullable[string](),
+		},
+		"empty note": {
+			note:             nullable.NewNullableWithValue(""),
+			expected:         " [terraform-create:0123456789abcdef]",
+			expectedStripped: nullable.NewNullableWithValue(""),
+		},
+		"note": {
+			note:             nullable.NewNullableWithValue("some note"),
+			expected:         "some note [terraform-create:0123456789abcdef]",
+			expectedStripped: nullable.NewNullableWithValue("some note"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			marked := testMarker.Mark(tc.note)
+
+			if marked.MustGet() != tc.expected {
+				t.Fatalf("expected note %q, got %q", tc.expected, marked.MustGet())
+			}
+
+			if !testMarker.IsIn(marked) {
+				t.Error("expected the marker to be in the marked note")
+			}
+
+			if stripped := util.WithoutIdempotencyMarkers(marked); !maps.Equal(stripped, tc.expectedStripped) {
+				t.Errorf("expected the stripped note %v, got %v", tc.expectedStripped, stripped)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMarker_IsIn(t *testing.T) {
+	testCases := map[string]struct {
+		note     nullable.Nullable[string]
+		expected bool
+	}{
+		"null note":      {note: nullable.NewNullNullable[string]()},
+		"unspecified":    {note: nullable.Nullable[string]{}},
+		"unmarked note":  {note: nullable.NewNullableWithValue("some note")},
+		"another marker": {note: nullable.NewNullableWithValue("note [terraform-create:fedcba9876543210]")},
+		"marked note": {
+			note:     nullable.NewNullableWithValue("note [terraform-create:0123456789abcdef]"),
+			expected: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if isIn := testMarker.IsIn(tc.note); isIn != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, isIn)
+			}
+		})
+	}
+}
+
+func TestFindMarked(t *testing.T) {
+	type item struct {
+		id   string
+		note nullable.Nullable[string]
+	}
+
+	unmarked := item{id: "a", note: nullable.NewNullableWithValue("some note")}
+	marked := item{id: "b", note: testMarker.Mark(nullable.NewNullableWithValue("some note"))}
+	alsoMarked := item{id: "c", note: testMarker.Mark(nullable.NewNullNullable[string]())}
+	idAndNote := func(i item) (string, nullable.Nullable[string]) { return i.id, i.note }
+
+	testCases := map[string]struct {
+		items            []item
+		expectedId       string
+		expectedWarnings int
+		expectedErrors   int
+	}{
+		"no items": {},
+		"no marked item": {
+			items: []item{unmarked},
+		},
+		"one marked item": {
+			items:            []item{unmarked, marked},
+			expectedId:       "b",
+			expectedWarnings: 1,
+		},
+		"ambiguous marked items": {
+			items:          []item{marked, unmarked, alsoMarked},
+			expectedErrors: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			found := util.FindMarked(&diags, "Origin", testMarker, tc.items, idAndNote)
+
+			if tc.expectedId == "" && found != nil {
+				t.Errorf("expected no item, got %+v", *found)
+			}
+
+			if tc.expectedId != "" && (found == nil || found.id != tc.expectedId) {
+				t.Errorf("expected item %s, got %+v", tc.expectedId, found)
+			}
+
+			if diags.WarningsCount() != tc.expectedWarnings || diags.ErrorsCount() != tc.expectedErrors {
+				t.Errorf(
+					"expected %d warnings and %d errors, got %v",
+					tc.expectedWarnings,
+					tc.expectedErrors,
+					diags,
+				)
+			}
+		})
+	}
+}
+
+func TestIsLostResponse(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		"created":      {statusCode: http.StatusCreated},
+		"client error": {statusCode: http.StatusUnprocessableEntity},
+		"server error": {statusCode: http.StatusBadGateway, expected: true},
+		"deadline":     {err: context.DeadlineExceeded, expected: true},
+		"other error":  {err: errors.New("connection refused")},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			response := &cdn77.CdnAddResponse{HTTPResponse: &http.Response{StatusCode: tc.statusCode}}
+			if isLost := util.IsLostResponse(response, tc.err); isLost != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, isLost)
+			}
+		})
+	}
+}
+
+func TestLostResponseContext(t *testing.T) {
+	expired, cancelExpired := context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	ctx, cancel := util.LostResponseContext(expired)
+	defer cancel()
+
+	if ctx.Err() != nil {
+		t.Fatalf("expected a usable context, got %v", ctx.Err())
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected the context to have a deadline")
+	}
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = JsonType{}
+	_ basetypes.StringValuableWithSemanticEquals = JsonValue{}
+)
+
+// JsonType is a string type holding a JSON document. Its values are semantically equal if they represent the same
+// JSON, so the differences in whitespace or in the order of object keys don't cause diffs.
+type JsonType struct {
+	basetypes.StringType
+}
+
+func (JsonType) String() string {
+	return "util.JsonType"
+}
+
+func (JsonType) ValueType(context.Context) attr.Value {
+	return JsonValue{}
+}
+
+func (t JsonType) Equal(o attr.Type) bool {
+	other, ok := o.(JsonType)
+
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (JsonType) ValueFromString(
+	_ context.Context,
+	in basetypes.StringValue,
+) (basetypes.StringValuable, diag.Diagnostics) {
+	return JsonValue{StringValue: in}, nil
+}
+
+func (t JsonType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return JsonValue{StringValue: stringValue}, nil
+}
+
+type JsonValue struct {
+	basetypes.StringValue
+}
+
+func NewJsonValue(value string) JsonValue {
+	return JsonValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func NewJsonNull() JsonValue {
+	return JsonValue{StringValue: basetypes.NewStringNull()}
+}
+
+func NewJsonUnknown() JsonValue {
+	return JsonValue{StringValue: basetypes.NewStringUnknown()}
+}
+
+func (JsonValue) Type(context.Context) attr.Type {
+	return JsonType{}
+}
+
+func (v JsonValue) Equal(o attr.Value) bool {
+	other, ok := o.(JsonValue)
+
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v JsonValue) StringSemanticEquals(
+	_ context.Context,
+	newValuable basetypes.StringValuable,
+) (bool, diag.Diagnostics) {
+	newValue, ok := newValuable.(JsonValue)
+	if !ok {
+		return false, nil
+	}
+
+	return JsonEqual(v.ValueString(), newValue.ValueString()), nil
+}
+
+// JsonEqual reports whether both strings are valid JSON documents with the same content.
+func JsonEqual(a string, b string) bool {
+	var aData, bData any
+
+	if json.Unmarshal([]byte(a), &aData) != nil || json.Unmarshal([]byte(b), &bData) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(aData, bData)
+}
+
+// JsonRoundTrip converts the value to the target (e.g. a struct to a map) by marshalling it to JSON and back.
+func JsonRoundTrip(value any, target any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, target)
+}
@@ -94,3 +94,39 @@ func (r UniversalReader[M, R, Rok]) Fill(
 
 	return diags
 }
+
+// DataSourceReader reads the model of a data source using the reader of the corresponding resource. The data source
+// model leaves out the resource-only attributes, so it's converted from and to the resource model.
+type DataSourceReader[D any, M any, R Response, Rok any] struct {
+	reader    GenericReader[M, R, Rok]
+	toModel   func(data D) M
+	fromModel func(model M) D
+}
+
+func NewDataSourceReader[D any, M any, R Response, Rok any](
+	reader GenericReader[M, R, Rok],
+	toModel func(data D) M,
+	fromModel func(model M) D,
+) *UniversalReader[D, R, Rok] {
+	return NewUniversalReader[D, R, Rok](&DataSourceReader[D, M, R, Rok]{
+		reader:    reader,
+		toModel:   toModel,
+		fromModel: fromModel,
+	})
+}
+
+func (r *DataSourceReader[D, M, R, Rok]) ErrMessage() string {
+	return r.reader.ErrMessage()
+}
+
+func (r *DataSourceReader[D, M, R, Rok]) Fetch(
+	ctx context.Context,
+	client cdn77.ClientWithResponsesInterface,
+	data D,
+) (R, *Rok, error) {
+	return r.reader.Fetch(ctx, client, r.toModel(data))
+}
+
+func (r *DataSourceReader[D, M, R, Rok]) Process(ctx context.Context, data D, detail *Rok, diags *diag.Diagnostics) D {
+	return r.fromModel(r.reader.Process(ctx, r.toModel(data), detail, diags))
+}
@@ -11,27 +11,49 @@ import (
 type ResourceDataSourceSchemaConverter struct {
 	requiredAttrs       map[string]struct{}
 	requiredNestedAttrs map[string][]string
+	excludedAttrs       map[string]struct{}
+	excludedNestedAttrs map[string][]string
 }
 
 func NewResourceDataSourceSchemaConverter(requiredAttrs ...string) *ResourceDataSourceSchemaConverter {
-	requiredAttrsMap := make(map[string]struct{})
-	requiredNestedAttrsMap := make(map[string][]string)
+	requiredAttrsMap, requiredNestedAttrsMap := splitAttrPaths(requiredAttrs)
 
-	for _, requiredAttr := range requiredAttrs {
-		if i := strings.Index(requiredAttr, "."); i != -1 {
-			attr := requiredAttr[:i]
-			requiredNestedAttrsMap[attr] = append(requiredNestedAttrsMap[attr], requiredAttr[i+1:])
+	return &ResourceDataSourceSchemaConverter{
+		requiredAttrs:       requiredAttrsMap,
+		requiredNestedAttrs: requiredNestedAttrsMap,
+	}
+}
+
+// WithoutAttrs leaves out the given resource-only attributes (e.g. write-only ones) from the converted schema. Nested
+// attributes are given by their dot-separated path.
+func (c *ResourceDataSourceSchemaConverter) WithoutAttrs(attrs ...string) *ResourceDataSourceSchemaConverter {
+	c.excludedAttrs, c.excludedNestedAttrs = splitAttrPaths(attrs)
+
+	return c
+}
+
+func splitAttrPaths(paths []string) (map[string]struct{}, map[string][]string) {
+	attrs := make(map[string]struct{})
+	nestedAttrs := make(map[string][]string)
+
+	for _, p := range paths {
+		if i := strings.Index(p, "."); i != -1 {
+			attr := p[:i]
+			nestedAttrs[attr] = append(nestedAttrs[attr], p[i+1:])
 
 			continue
 		}
 
-		requiredAttrsMap[requiredAttr] = struct{}{}
+		attrs[p] = struct{}{}
 	}
 
-	return &ResourceDataSourceSchemaConverter{
-		requiredAttrs:       requiredAttrsMap,
-		requiredNestedAttrs: requiredNestedAttrsMap,
-	}
+	return attrs, nestedAttrs
+}
+
+func (c *ResourceDataSourceSchemaConverter) childConverter(name string) *ResourceDataSourceSchemaConverter {
+	converter := NewResourceDataSourceSchemaConverter(c.requiredNestedAttrs[name]...)
+
+	return converter.WithoutAttrs(c.excludedNestedAttrs[name]...)
 }
 
 func (c *ResourceDataSourceSchemaConverter) Convert(rsc rsc_schema.Schema) ds_schema.Schema {
@@ -53,6 +75,10 @@ func (c *ResourceDataSourceSchemaConverter) convertAttributes(
 	dsAttrs := make(map[string]ds_schema.Attribute, len(rscAttrs))
 
 	for name, rscAttr := range rscAttrs {
+		if _, isExcluded := c.excludedAttrs[name]; isExcluded {
+			continue
+		}
+
 		_, isRequired := c.requiredAttrs[name]
 		var dsAttr ds_schema.Attribute
 
@@ -126,7 +152,7 @@ func (c *ResourceDataSourceSchemaConverter) convertAttributes(
 				Validators:          If(isRequired, rscAttr.Validators, nil),
 			}
 		case rsc_schema.SingleNestedAttribute:
-			childConverter := NewResourceDataSourceSchemaConverter(c.requiredNestedAttrs[name]...)
+			childConverter := c.childConverter(name)
 			dsAttr = ds_schema.SingleNestedAttribute{
 				Attributes:          childConverter.convertAttributes(rscAttr.Attributes),
 				CustomType:          rscAttr.CustomType,
@@ -138,6 +164,22 @@ func (c *ResourceDataSourceSchemaConverter) convertAttributes(
 				DeprecationMessage:  rscAttr.DeprecationMessage,
 				Validators:          If(isRequired, rscAttr.Validators, nil),
 			}
+		case rsc_schema.ListNestedAttribute:
+			childConverter := c.childConverter(name)
+			dsAttr = ds_schema.ListNestedAttribute{
+				NestedObject: ds_schema.NestedAttributeObject{
+					Attributes: childConverter.convertAttributes(rscAttr.NestedObject.Attributes),
+					CustomType: rscAttr.NestedObject.CustomType,
+				},
+				CustomType:          rscAttr.CustomType,
+				Required:            isRequired,
+				Computed:            !isRequired,
+				Sensitive:           rscAttr.Sensitive,
+				Description:         rscAttr.Description,
+				MarkdownDescription: rscAttr.MarkdownDescription,
+				DeprecationMessage:  rscAttr.DeprecationMessage,
+				Validators:          If(isRequired, rscAttr.Validators, nil),
+			}
 		default:
 			const message = `Resource to DataSource schema converter encountered unsupported Attribute type "%T"`
 